@@ -0,0 +1,249 @@
+package algorithm
+
+import "errors"
+
+// builder 按key升序在尾部追加结点，用于O(n)构建跳表
+type builder[K, V any] struct {
+	l     *SkipList[K, V]
+	last  [MaxLevelLimit]*Node[K, V] // 每层最后一个结点
+	ranks [MaxLevelLimit]int         // 每层最后一个结点的位置
+}
+
+// newBuilder 清空跳表并返回构建器
+func newBuilder[K, V any](l *SkipList[K, V]) *builder[K, V] {
+	l.clear()
+	b := &builder[K, V]{l: l}
+	for i := range b.last {
+		b.last[i] = l.head
+	}
+	return b
+}
+
+// append 在尾部追加层数为height的结点，调用方保证key不小于已追加的key
+func (b *builder[K, V]) append(key K, value V, height int) {
+	l := b.l
+	pos := int(l.len) + 1
+	node := newNode(key, value, height)
+	for i := 0; i < height; i++ {
+		b.last[i].levels[i].next = node
+		b.last[i].levels[i].span = pos - b.ranks[i]
+		b.last[i] = node
+		b.ranks[i] = pos
+	}
+	if l.tail != nil {
+		node.backward = l.tail
+	}
+	l.tail = node
+	l.len++
+	if int(l.layer) < height {
+		l.layer = int32(height)
+	}
+}
+
+// finish 设置每层最后一个结点的跨度
+func (b *builder[K, V]) finish() {
+	l := b.l
+	for i := 0; i < int(l.layer); i++ {
+		b.last[i].levels[i].span = int(l.len) - b.ranks[i]
+	}
+	l.reset()
+}
+
+// ErrBatchLength keys 与 values 的长度不一致
+var ErrBatchLength = errors.New("skiplist: keys and values length mismatch")
+
+// ErrUnsorted keys 没有按升序排列
+var ErrUnsorted = errors.New("skiplist: keys are not sorted")
+
+// FromSorted 用已按升序排列的keys构建跳表，原有的结点会被清空
+// 结点层数按位置确定（每 1/probability 个结点晋升一层），时间复杂度为O(n)
+// 非multimap模式下keys不能重复
+func (l *SkipList[K, V]) FromSorted(keys []K, values []V) error {
+	if len(keys) != len(values) {
+		return ErrBatchLength
+	}
+	for i := 1; i < len(keys); i++ {
+		c := l.cmp(keys[i-1], keys[i])
+		if c > 0 || c == 0 && !l.opts.duplicates {
+			return ErrUnsorted
+		}
+	}
+
+	b := newBuilder(l)
+	for i := range keys {
+		b.append(keys[i], values[i], l.balancedLevel(i+1))
+	}
+	b.finish()
+	return nil
+}
+
+// balancedLevel 第pos个结点的层数：pos 每能被 1/probability 整除一次晋升一层
+func (l *SkipList[K, V]) balancedLevel(pos int) int {
+	step := int(1/l.opts.probability + 0.5)
+	if step < 2 {
+		step = 2
+	}
+	level := 1
+	for level < l.opts.maxLevel && pos%step == 0 {
+		pos /= step
+		level++
+	}
+	return level
+}
+
+// InsertBatch 批量插入，key已存在时覆盖value
+// 相邻的key按升序排列时复用上一次的查找路径，从底层向上只回溯需要移动的层
+func (l *SkipList[K, V]) InsertBatch(keys []K, values []V) error {
+	if len(keys) != len(values) {
+		return ErrBatchLength
+	}
+
+	var path searchPath[K, V]
+	var rank int
+	for i, key := range keys {
+		if i == 0 || l.cmp(keys[i-1], key) > 0 {
+			rank = l.fillPath(key, l.opts.duplicates, &path)
+		} else {
+			rank = l.fingerPath(key, &path)
+		}
+
+		if !l.opts.duplicates {
+			// 批次内重复的key：上一次插入的结点就是前驱
+			if pre := path.pres[0]; pre != l.head && l.cmp(pre.key, key) == 0 {
+				l.setValue(pre, rank, values[i])
+				continue
+			}
+			if node := path.pres[0].levels[0].next; node != nil && l.cmp(node.key, key) == 0 {
+				l.setValue(node, rank+1, values[i])
+				continue
+			}
+		}
+		node := l.insertAt(&path, rank, key, values[i])
+		// 新结点成为后续key在其所在层的前驱
+		for level := range node.levels {
+			path.pres[level] = node
+			path.ranks[level] = rank + 1
+		}
+	}
+	return nil
+}
+
+// fingerPath 从上一次的查找路径出发，更新为key的查找路径，调用方保证key不小于上一个key
+func (l *SkipList[K, V]) fingerPath(key K, path *searchPath[K, V]) int {
+	// 找到需要向右移动的最高层，更高的层前驱不变
+	top := -1
+	for i := 0; i < int(l.layer); i++ {
+		next := path.pres[i].levels[i].next
+		if next == nil || !l.before(next.key, key) {
+			break
+		}
+		top = i
+	}
+	if top < 0 {
+		return path.ranks[0]
+	}
+
+	curNode, rank := path.pres[top], path.ranks[top]
+	for i := top; i >= 0; i-- {
+		if path.ranks[i] > rank {
+			curNode, rank = path.pres[i], path.ranks[i]
+		}
+		for next := curNode.levels[i].next; next != nil && l.before(next.key, key); next = curNode.levels[i].next {
+			rank += curNode.levels[i].span
+			curNode = next
+		}
+		path.pres[i] = curNode
+		path.ranks[i] = rank
+	}
+	return rank
+}
+
+// DeleteRange 删除key在 [lo, hi) 内的结点，返回删除的个数
+func (l *SkipList[K, V]) DeleteRange(lo, hi K) int {
+	var path searchPath[K, V]
+	l.fillPath(lo, false, &path)
+	count := 0
+	for node := path.pres[0].levels[0].next; node != nil && l.cmp(node.key, hi) < 0; node = node.levels[0].next {
+		count++
+	}
+	l.unlinkRun(&path, count)
+	return count
+}
+
+// DeleteRangeByRank 删除升序排名在 [start, stop] 内的结点，返回删除的个数
+// 与 RangeByRank 一致，负数表示从末尾开始计数
+func (l *SkipList[K, V]) DeleteRangeByRank(start, stop int) int {
+	n := int(l.len)
+	if start < 0 {
+		start += n
+	}
+	if stop < 0 {
+		stop += n
+	}
+	if start < 0 {
+		start = 0
+	}
+	if stop >= n {
+		stop = n - 1
+	}
+	if start > stop {
+		return 0
+	}
+
+	var path searchPath[K, V]
+	traversed := 0
+	curNode := l.head
+	for i := int(l.layer) - 1; i >= 0; i-- {
+		for curNode.levels[i].next != nil && traversed+curNode.levels[i].span <= start {
+			traversed += curNode.levels[i].span
+			curNode = curNode.levels[i].next
+		}
+		path.pres[i] = curNode
+		path.ranks[i] = traversed
+	}
+	count := stop - start + 1
+	l.unlinkRun(&path, count)
+	return count
+}
+
+// unlinkRun 一次性删除查找路径最底层前驱之后的count个连续结点
+func (l *SkipList[K, V]) unlinkRun(path *searchPath[K, V], count int) {
+	if count <= 0 {
+		return
+	}
+	last := path.ranks[0] + count
+	var removed []*Node[K, V]
+	if len(l.observers) > 0 {
+		removed = make([]*Node[K, V], 0, count)
+		for node := path.pres[0].levels[0].next; len(removed) < count; node = node.levels[0].next {
+			removed = append(removed, node)
+		}
+	}
+	for i := 0; i < int(l.layer); i++ {
+		pre := path.pres[i]
+		dist := pre.levels[i].span
+		next := pre.levels[i].next
+		for next != nil && path.ranks[i]+dist <= last {
+			dist += next.levels[i].span
+			next = next.levels[i].next
+		}
+		pre.levels[i].next = next
+		pre.levels[i].span = dist - count
+	}
+
+	pre := path.pres[0]
+	if pre == l.head {
+		pre = nil
+	}
+	if next := path.pres[0].levels[0].next; next != nil {
+		next.backward = pre
+	} else {
+		l.tail = pre
+	}
+	l.len -= int32(count)
+	l.trimLayer()
+	l.shadowRemove(path.ranks[0]+1, count)
+	for i, node := range removed {
+		l.notifyDelete(node, path.ranks[0]+i)
+	}
+}
@@ -0,0 +1,152 @@
+package algorithm
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestSkipListFromSorted(t *testing.T) {
+	keys := make([]int, 1000)
+	for i := range keys {
+		keys[i] = i * 2
+	}
+	l := NewOrdered[int, int]()
+	l.Insert(-1, -1)
+	if err := l.FromSorted(keys, keys); err != nil {
+		t.Fatal(err)
+	}
+	checkSpans(t, l)
+	if !equalInts(l.Sort(), keys) {
+		t.Fatal("sort mismatch after FromSorted")
+	}
+	counts := levelCounts(l)
+	// 按位置晋升，每层结点数为下一层的一半
+	if got := counts[len(counts)-2]; got != 500 {
+		t.Fatalf("level counts = %v", counts)
+	}
+	l.Insert(5, 5)
+	l.Delete(10)
+	checkSpans(t, l)
+
+	if err := l.FromSorted([]int{1, 2}, []int{1}); err != ErrBatchLength {
+		t.Fatalf("err = %v, want ErrBatchLength", err)
+	}
+	if err := l.FromSorted([]int{1, 1}, []int{1, 2}); err != ErrUnsorted {
+		t.Fatalf("err = %v, want ErrUnsorted", err)
+	}
+	dup := NewOrdered[int, int](WithDuplicates())
+	if err := dup.FromSorted([]int{1, 1, 2}, []int{1, 2, 3}); err != nil || dup.Count(1) != 2 {
+		t.Fatalf("duplicates FromSorted: %v, count = %d", err, dup.Count(1))
+	}
+}
+
+func TestSkipListInsertBatch(t *testing.T) {
+	l := NewOrdered[int, int](WithRandSource(rand.NewSource(7)))
+	for i := 0; i < 300; i += 3 {
+		l.Insert(i, i)
+	}
+	// 有序的部分复用查找路径，逆序的key重新查找
+	keys := []int{}
+	for i := 0; i < 300; i++ {
+		keys = append(keys, i)
+	}
+	keys = append(keys, 50, 10, 400, 399)
+	values := make([]int, len(keys))
+	for i, k := range keys {
+		values[i] = k * 10
+	}
+	if err := l.InsertBatch(keys, values); err != nil {
+		t.Fatal(err)
+	}
+	checkSpans(t, l)
+	if l.Len() != 302 {
+		t.Fatalf("len = %d, want 302", l.Len())
+	}
+	for _, k := range keys {
+		if node, ok := l.Find(k, 0); !ok || node.GetValue() != k*10 {
+			t.Fatalf("find %d = %v, %v", k, node.GetValue(), ok)
+		}
+	}
+
+	// 非multimap模式下批次内重复的key覆盖前一个value
+	uniq := NewOrdered[int, int]()
+	uniq.Insert(5, 0)
+	if err := uniq.InsertBatch([]int{3, 3, 4, 5, 5, 5}, []int{1, 2, 3, 4, 5, 6}); err != nil {
+		t.Fatal(err)
+	}
+	checkSpans(t, uniq)
+	if err := uniq.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	var got []int
+	for k, v := range uniq.All() {
+		got = append(got, k, v)
+	}
+	if !equalInts(got, []int{3, 2, 4, 3, 5, 6}) {
+		t.Fatalf("unique batch = %v", got)
+	}
+
+	dup := NewOrdered[int, int](WithDuplicates())
+	dup.Insert(2, 0)
+	if err := dup.InsertBatch([]int{1, 2, 2, 3}, []int{1, 2, 3, 4}); err != nil {
+		t.Fatal(err)
+	}
+	checkSpans(t, dup)
+	if !equalInts(dup.FindAll(2), []int{0, 2, 3}) {
+		t.Fatalf("FindAll(2) = %v", dup.FindAll(2))
+	}
+	if err := l.InsertBatch([]int{1}, nil); err != ErrBatchLength {
+		t.Fatalf("err = %v, want ErrBatchLength", err)
+	}
+}
+
+func TestSkipListDeleteRange(t *testing.T) {
+	l := NewOrdered[int, int]()
+	for i := 0; i < 500; i++ {
+		l.Insert(i, i)
+	}
+	if n := l.DeleteRange(100, 200); n != 100 {
+		t.Fatalf("DeleteRange = %d, want 100", n)
+	}
+	checkSpans(t, l)
+	if _, ok := l.Find(150, 0); ok {
+		t.Fatal("deleted key still found")
+	}
+	if rank, _ := l.Rank(200); rank != 100 {
+		t.Fatalf("rank of 200 = %d, want 100", rank)
+	}
+	if n := l.DeleteRange(450, 1000); n != 50 {
+		t.Fatalf("DeleteRange tail = %d, want 50", n)
+	}
+	checkSpans(t, l)
+	if n := l.DeleteRange(600, 700); n != 0 {
+		t.Fatalf("DeleteRange empty = %d", n)
+	}
+
+	if n := l.DeleteRangeByRank(0, 9); n != 10 {
+		t.Fatalf("DeleteRangeByRank = %d, want 10", n)
+	}
+	checkSpans(t, l)
+	if node, _ := l.GetMin(); node.GetKey() != 10 {
+		t.Fatalf("min = %d, want 10", node.GetKey())
+	}
+	if n := l.DeleteRangeByRank(-5, -1); n != 5 {
+		t.Fatalf("DeleteRangeByRank negative = %d, want 5", n)
+	}
+	checkSpans(t, l)
+	if node, _ := l.GetMax(); node.GetKey() != 444 {
+		t.Fatalf("max = %d, want 444", node.GetKey())
+	}
+	if n := l.DeleteRangeByRank(5, 2); n != 0 {
+		t.Fatalf("DeleteRangeByRank empty = %d", n)
+	}
+
+	n := int(l.Len())
+	if got := l.DeleteRangeByRank(0, -1); got != n {
+		t.Fatalf("DeleteRangeByRank all = %d, want %d", got, n)
+	}
+	checkSpans(t, l)
+	if l.layer != 0 || l.tail != nil {
+		t.Fatalf("list not empty: layer = %d", l.layer)
+	}
+}
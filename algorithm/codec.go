@@ -0,0 +1,96 @@
+package algorithm
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"math"
+)
+
+// Codec 编解码器，用于持久化跳表的key和value
+type Codec[T any] interface {
+	Encode(v T) ([]byte, error)
+	Decode(data []byte) (T, error)
+}
+
+// ErrCodecData 解码的数据格式错误
+var ErrCodecData = errors.New("codec: invalid data")
+
+// Integer 整数类型
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// StringCodec 字符串编解码器
+type StringCodec struct{}
+
+func (StringCodec) Encode(v string) ([]byte, error) {
+	return []byte(v), nil
+}
+
+func (StringCodec) Decode(data []byte) (string, error) {
+	return string(data), nil
+}
+
+// BytesCodec 字节切片编解码器
+type BytesCodec struct{}
+
+func (BytesCodec) Encode(v []byte) ([]byte, error) {
+	return v, nil
+}
+
+func (BytesCodec) Decode(data []byte) ([]byte, error) {
+	return append([]byte(nil), data...), nil
+}
+
+// IntCodec 整数编解码器，使用varint编码
+type IntCodec[T Integer] struct{}
+
+func (IntCodec[T]) Encode(v T) ([]byte, error) {
+	if T(0)-1 < 0 {
+		return binary.AppendVarint(nil, int64(v)), nil
+	}
+	return binary.AppendUvarint(nil, uint64(v)), nil
+}
+
+func (IntCodec[T]) Decode(data []byte) (T, error) {
+	if T(0)-1 < 0 {
+		v, n := binary.Varint(data)
+		if n != len(data) || n == 0 {
+			return 0, ErrCodecData
+		}
+		return T(v), nil
+	}
+	v, n := binary.Uvarint(data)
+	if n != len(data) || n == 0 {
+		return 0, ErrCodecData
+	}
+	return T(v), nil
+}
+
+// Float64Codec float64 编解码器
+type Float64Codec struct{}
+
+func (Float64Codec) Encode(v float64) ([]byte, error) {
+	return binary.BigEndian.AppendUint64(nil, math.Float64bits(v)), nil
+}
+
+func (Float64Codec) Decode(data []byte) (float64, error) {
+	if len(data) != 8 {
+		return 0, ErrCodecData
+	}
+	return math.Float64frombits(binary.BigEndian.Uint64(data)), nil
+}
+
+// JSONCodec 使用 encoding/json 的编解码器，适用于任意可以json序列化的类型
+type JSONCodec[T any] struct{}
+
+func (JSONCodec[T]) Encode(v T) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (JSONCodec[T]) Decode(data []byte) (T, error) {
+	var v T
+	err := json.Unmarshal(data, &v)
+	return v, err
+}
@@ -0,0 +1,139 @@
+package algorithm
+
+// DimRule 次要维度的约束，target 为发起匹配一方的值，cur 为候选一方的值
+type DimRule func(target, cur int64) bool
+
+// DimEqual 两个值必须相等
+func DimEqual() DimRule {
+	return func(target, cur int64) bool {
+		return target == cur
+	}
+}
+
+// DimWithin 两个值的差不超过d
+func DimWithin(d int64) DimRule {
+	return func(target, cur int64) bool {
+		diff := target - cur
+		if diff < 0 {
+			diff = -diff
+		}
+		return diff <= d
+	}
+}
+
+// DimIn 候选一方的值必须在values中
+func DimIn(values ...int64) DimRule {
+	return func(_, cur int64) bool {
+		for _, v := range values {
+			if v == cur {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+type compositeDim struct {
+	name  string
+	value int64
+	rule  DimRule
+}
+
+// CompositeKey 多维度的匹配key，实现 Key 接口
+// 跳表只按主维度（分数相同按ID）排序，Check 检查主维度的分差与其余维度的约束，
+// 双方各自的约束都满足时才视为匹配
+//
+//	key := NewCompositeKey(id, rating).
+//		WithTolerance(100).
+//		WithDim("region", region, DimEqual()).
+//		WithDim("latency", bucket, DimWithin(1))
+type CompositeKey struct {
+	id        int64
+	primary   int64
+	tolerance int64
+	dims      []compositeDim
+}
+
+// NewCompositeKey 创建以primary为主维度的key，id 用于区分主维度相同的key
+// 默认主维度的分差不限制
+func NewCompositeKey(id, primary int64) *CompositeKey {
+	return &CompositeKey{id: id, primary: primary, tolerance: -1}
+}
+
+// WithTolerance 设置主维度可接受的分差，小于0表示不限制
+func (k *CompositeKey) WithTolerance(tolerance int64) *CompositeKey {
+	k.tolerance = tolerance
+	return k
+}
+
+// WithDim 添加次要维度，rule 为nil时该维度只用于满足对方的约束
+func (k *CompositeKey) WithDim(name string, value int64, rule DimRule) *CompositeKey {
+	k.dims = append(k.dims, compositeDim{name: name, value: value, rule: rule})
+	return k
+}
+
+// ID 返回key的ID
+func (k *CompositeKey) ID() int64 {
+	return k.id
+}
+
+// Primary 返回主维度的值
+func (k *CompositeKey) Primary() int64 {
+	return k.primary
+}
+
+// Dim 返回次要维度的值
+func (k *CompositeKey) Dim(name string) (int64, bool) {
+	for _, d := range k.dims {
+		if d.name == name {
+			return d.value, true
+		}
+	}
+	return 0, false
+}
+
+// Equal 主维度与ID都相同视为同一个key，与 Less 的排序一致
+// 同一个ID的主维度变化时，需要先用原来的主维度 Delete，再插入新的key，
+// 否则跳表中会同时存在两个该ID的结点，用新的主维度 Delete 也找不到旧的结点
+func (k *CompositeKey) Equal(then Key) bool {
+	o := then.(*CompositeKey)
+	return k.primary == o.primary && k.id == o.id
+}
+
+// Less 先按主维度排序，相同时按ID排序
+func (k *CompositeKey) Less(then Key) bool {
+	o := then.(*CompositeKey)
+	if k.primary != o.primary {
+		return k.primary < o.primary
+	}
+	return k.id < o.id
+}
+
+// Check 双方的主维度分差与次要维度约束都满足时返回true
+func (k *CompositeKey) Check(then Key) bool {
+	o := then.(*CompositeKey)
+	return k.id != o.id && k.accept(o) && o.accept(k)
+}
+
+// accept k 的约束是否接受候选o
+func (k *CompositeKey) accept(o *CompositeKey) bool {
+	if k.tolerance >= 0 {
+		diff := k.primary - o.primary
+		if diff < 0 {
+			diff = -diff
+		}
+		if diff > k.tolerance {
+			return false
+		}
+	}
+	for _, d := range k.dims {
+		if d.rule == nil {
+			continue
+		}
+		v, ok := o.Dim(d.name)
+		if !ok || !d.rule(d.value, v) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,132 @@
+package algorithm
+
+import "testing"
+
+func TestCompositeKey(t *testing.T) {
+	player := func(id, rating, region, latency int64) *CompositeKey {
+		return NewCompositeKey(id, rating).
+			WithTolerance(200).
+			WithDim("region", region, DimEqual()).
+			WithDim("latency", latency, DimWithin(1))
+	}
+	l := NewKeySkipList()
+	l.Insert(player(1, 1000, 1, 0), "same rating, other region")
+	l.Insert(player(2, 1050, 2, 3), "same region, latency too far")
+	l.Insert(player(3, 1150, 2, 2), "match")
+	l.Insert(player(4, 950, 2, 1), "closer below")
+	l.Insert(player(5, 1500, 2, 1), "rating too far")
+
+	// 从1010向两侧按位置交替查找：1050、1000、1150
+	target := player(-1, 1010, 2, 1)
+	node, ok := l.Find(target, 0)
+	if !ok || node.GetValue() != "match" {
+		t.Fatalf("find = %v, %v", node.GetValue(), ok)
+	}
+	l.Delete(player(3, 1150, 2, 2))
+	if node, ok = l.Find(target, 0); !ok || node.GetValue() != "closer below" {
+		t.Fatalf("find = %v, %v", node.GetValue(), ok)
+	}
+	// 只检查两个最近的结点（1050 与 1000）
+	if _, ok = l.Find(target, 2); ok {
+		t.Fatal("found match beyond search limit")
+	}
+
+	// 候选一方的约束也要满足
+	strict := NewCompositeKey(6, 1010).WithTolerance(0)
+	l.Insert(strict, "strict")
+	// 1050 不接受没有region的key，1010 只接受分差为0的key
+	if node, ok = l.Find(NewCompositeKey(-2, 1012), 2); ok {
+		t.Fatalf("matched %v against its constraints", node.GetValue())
+	}
+	if node, ok = l.Find(NewCompositeKey(-3, 1010), 1); !ok || node.GetValue() != "strict" {
+		t.Fatalf("find = %v, %v", node.GetValue(), ok)
+	}
+	if v, ok := target.Dim("latency"); !ok || v != 1 || target.ID() != -1 || target.Primary() != 1010 {
+		t.Fatal("accessors")
+	}
+	if !DimIn(1, 2)(0, 2) || DimIn(1, 2)(0, 3) {
+		t.Fatal("DimIn")
+	}
+}
+
+func TestCompositeKeyUpdate(t *testing.T) {
+	l := NewKeySkipList()
+	l.Insert(NewCompositeKey(1, 100), "a")
+	l.Insert(NewCompositeKey(2, 70), "b")
+	// 主维度不同是不同的key，不会覆盖
+	l.Insert(NewCompositeKey(1, 50), "a2")
+	if l.Len() != 3 {
+		t.Fatalf("len = %d, want 3", l.Len())
+	}
+	// 主维度相同时覆盖value
+	l.Insert(NewCompositeKey(1, 50), "a3")
+	if l.Len() != 3 {
+		t.Fatalf("len = %d after overwrite, want 3", l.Len())
+	}
+
+	// 更新主维度：用原来的主维度删除
+	l.Delete(NewCompositeKey(1, 70))
+	if l.Len() != 3 {
+		t.Fatal("deleted with a primary that was never inserted")
+	}
+	l.Delete(NewCompositeKey(1, 100))
+	if l.Len() != 2 {
+		t.Fatalf("len = %d after delete, want 2", l.Len())
+	}
+	if err := l.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if node, ok := l.GetMin(); !ok || node.GetValue() != "a3" {
+		t.Fatalf("min = %v, %v", node.GetValue(), ok)
+	}
+}
+
+func TestKeySkipListFindUnlimited(t *testing.T) {
+	l := NewKeySkipList()
+	for i := int64(0); i < 2*DefaultSearchLimit; i++ {
+		l.Insert(NewCompositeKey(i, i).WithDim("region", 1, nil), i)
+	}
+	l.Insert(NewCompositeKey(1000, 1000).WithDim("region", 2, nil), "far")
+	target := NewCompositeKey(-1, -1).WithDim("region", 2, DimEqual())
+	// 旧版的 Find(key, 0) 不限制查找次数
+	if node, ok := l.Find(target, 0); !ok || node.GetValue() != "far" {
+		t.Fatalf("find = %v, %v", node.GetValue(), ok)
+	}
+	if _, ok := l.Find(target, DefaultSearchLimit); ok {
+		t.Fatal("found match beyond search limit")
+	}
+}
+
+func TestSkipListFindBothDirections(t *testing.T) {
+	l := NewOrdered[int, int]()
+	for _, k := range []int{10, 20, 30, 40, 50} {
+		l.Insert(k, k)
+	}
+	var checked []int
+	l.SetMatch(func(target, cur int) bool {
+		checked = append(checked, cur)
+		return cur == 10
+	})
+	if node, ok := l.Find(33, 0); !ok || node.GetKey() != 10 {
+		t.Fatalf("find = %v, %v", node.GetKey(), ok)
+	}
+	if !equalInts(checked, []int{40, 30, 50, 20, 10}) {
+		t.Fatalf("checked = %v", checked)
+	}
+	checked = nil
+	if _, ok := l.Find(33, 3); ok || len(checked) != 3 {
+		t.Fatalf("search limit ignored, checked = %v", checked)
+	}
+
+	// 0 使用默认的上限，负数不限制
+	for i := 100; i < 300; i++ {
+		l.Insert(i, i)
+	}
+	checked = nil
+	if _, ok := l.Find(1000, 0); ok || len(checked) != DefaultSearchLimit {
+		t.Fatalf("default limit: checked %d", len(checked))
+	}
+	if node, ok := l.Find(1000, -1); !ok || node.GetKey() != 10 {
+		t.Fatalf("unlimited find = %v, %v", node.GetKey(), ok)
+	}
+}
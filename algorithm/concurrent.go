@@ -0,0 +1,372 @@
+package algorithm
+
+import (
+	"math/rand"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// concurrentMaxLevel 并发跳表的最大层数
+const concurrentMaxLevel = 32
+
+// cnode 并发跳表结点
+// next 与 value 通过原子操作读写，读操作不加锁；
+// 修改结点前后的链接时需要持有前驱结点的锁
+type cnode[K, V any] struct {
+	key         K
+	value       atomic.Pointer[V]
+	next        []atomic.Pointer[cnode[K, V]]
+	mu          sync.Mutex
+	marked      atomic.Bool // 已被逻辑删除
+	fullyLinked atomic.Bool // 所有层都已链接完成
+}
+
+func (n *cnode[K, V]) topLayer() int {
+	return len(n.next) - 1
+}
+
+// ConcurrentSkipList 并发安全的跳表
+// 基于 lazy skiplist 实现：查找不加锁，插入删除只锁定受影响的前驱结点
+type ConcurrentSkipList[K, V any] struct {
+	head  *cnode[K, V]
+	len   atomic.Int32
+	cmp   func(a, b K) int
+	match func(target, cur K) bool
+}
+
+// NewConcurrent 根据比较函数创建并发跳表
+func NewConcurrent[K, V any](cmp func(a, b K) int) *ConcurrentSkipList[K, V] {
+	head := &cnode[K, V]{next: make([]atomic.Pointer[cnode[K, V]], concurrentMaxLevel)}
+	head.fullyLinked.Store(true)
+	return &ConcurrentSkipList[K, V]{head: head, cmp: cmp}
+}
+
+// SetMatch 设置Find使用的匹配函数，需要在并发使用前设置
+func (l *ConcurrentSkipList[K, V]) SetMatch(match func(target, cur K) bool) {
+	l.match = match
+}
+
+// Len 结点个数
+func (l *ConcurrentSkipList[K, V]) Len() int32 {
+	return l.len.Load()
+}
+
+// Insert 插入节点，key已存在时覆盖value
+func (l *ConcurrentSkipList[K, V]) Insert(key K, value V) {
+	topLayer := l.randomLayer()
+	var preds, succs [concurrentMaxLevel]*cnode[K, V]
+	for {
+		lFound := l.findNode(key, &preds, &succs)
+		if lFound != -1 {
+			nodeFound := succs[lFound]
+			if !nodeFound.marked.Load() {
+				// 等待并发的插入完成后再覆盖
+				for !nodeFound.fullyLinked.Load() {
+					runtime.Gosched()
+				}
+				nodeFound.value.Store(&value)
+				return
+			}
+			// 结点正在被删除，重试
+			continue
+		}
+
+		highestLocked, valid := -1, true
+		var prevPred *cnode[K, V]
+		for layer := 0; valid && layer <= topLayer; layer++ {
+			pred, succ := preds[layer], succs[layer]
+			if pred != prevPred {
+				pred.mu.Lock()
+				highestLocked = layer
+				prevPred = pred
+			}
+			valid = !pred.marked.Load() && (succ == nil || !succ.marked.Load()) &&
+				pred.next[layer].Load() == succ
+		}
+		if !valid {
+			unlockPreds(&preds, highestLocked)
+			continue
+		}
+
+		newNode := &cnode[K, V]{key: key, next: make([]atomic.Pointer[cnode[K, V]], topLayer+1)}
+		newNode.value.Store(&value)
+		for layer := 0; layer <= topLayer; layer++ {
+			newNode.next[layer].Store(succs[layer])
+		}
+		for layer := 0; layer <= topLayer; layer++ {
+			preds[layer].next[layer].Store(newNode)
+		}
+		newNode.fullyLinked.Store(true)
+		unlockPreds(&preds, highestLocked)
+		l.len.Add(1)
+		return
+	}
+}
+
+// Delete 删除结点，返回是否删除成功
+func (l *ConcurrentSkipList[K, V]) Delete(key K) bool {
+	var victim *cnode[K, V]
+	var preds, succs [concurrentMaxLevel]*cnode[K, V]
+	isMarked, topLayer := false, -1
+	for {
+		lFound := l.findNode(key, &preds, &succs)
+		if !isMarked {
+			if lFound == -1 {
+				return false
+			}
+			victim = succs[lFound]
+			if !victim.fullyLinked.Load() || victim.topLayer() != lFound || victim.marked.Load() {
+				return false
+			}
+			topLayer = victim.topLayer()
+			victim.mu.Lock()
+			if victim.marked.Load() {
+				victim.mu.Unlock()
+				return false
+			}
+			victim.marked.Store(true)
+			isMarked = true
+		}
+
+		highestLocked, valid := -1, true
+		var prevPred *cnode[K, V]
+		for layer := 0; valid && layer <= topLayer; layer++ {
+			pred := preds[layer]
+			if pred != prevPred {
+				pred.mu.Lock()
+				highestLocked = layer
+				prevPred = pred
+			}
+			valid = !pred.marked.Load() && pred.next[layer].Load() == victim
+		}
+		if !valid {
+			unlockPreds(&preds, highestLocked)
+			continue
+		}
+
+		for layer := topLayer; layer >= 0; layer-- {
+			preds[layer].next[layer].Store(victim.next[layer].Load())
+		}
+		victim.mu.Unlock()
+		unlockPreds(&preds, highestLocked)
+		l.len.Add(-1)
+		return true
+	}
+}
+
+// Get 查找key对应的value
+func (l *ConcurrentSkipList[K, V]) Get(key K) (value V, ok bool) {
+	pred := l.head
+	for layer := concurrentMaxLevel - 1; layer >= 0; layer-- {
+		curr := pred.next[layer].Load()
+		for curr != nil && l.cmp(curr.key, key) < 0 {
+			pred = curr
+			curr = pred.next[layer].Load()
+		}
+		if curr != nil && l.cmp(curr.key, key) == 0 {
+			if !curr.fullyLinked.Load() || curr.marked.Load() {
+				return
+			}
+			return *curr.value.Load(), true
+		}
+	}
+	return
+}
+
+// Find 查找结点，语义与 SkipList.Find 相同：
+// key相等的结点优先返回；设置了匹配函数且key不存在时，从key所在的位置开始向两侧交替查找，
+// 先右后左、按位置由近到远，返回第一个 match 为true的结点
+// searchLimit 为最多调用 match 的次数，0 表示使用 DefaultSearchLimit，负数表示不限制
+// 没有后向指针，向左每走一步都需要重新定位前驱
+func (l *ConcurrentSkipList[K, V]) Find(key K, searchLimit int) (k K, v V, ok bool) {
+	if searchLimit == 0 {
+		searchLimit = DefaultSearchLimit
+	}
+	right := l.nextLive(l.lastLess(key))
+	if right != nil && l.cmp(right.key, key) == 0 {
+		return right.key, *right.value.Load(), true
+	}
+	if l.match == nil {
+		return
+	}
+
+	left := l.lastBefore(func(k K) bool { return l.cmp(k, key) < 0 })
+	checked := 0
+	for left != nil || right != nil {
+		for _, node := range [2]*cnode[K, V]{right, left} {
+			if node == nil {
+				continue
+			}
+			if searchLimit > 0 && checked >= searchLimit {
+				return
+			}
+			checked++
+			if l.match(key, node.key) {
+				return node.key, *node.value.Load(), true
+			}
+		}
+		if right != nil {
+			right = l.nextLive(right)
+		}
+		if left != nil {
+			bound := left.key
+			left = l.lastBefore(func(k K) bool { return l.cmp(k, bound) < 0 })
+		}
+	}
+	return
+}
+
+// FindLeft 返回key左侧（小于key）最近的结点
+func (l *ConcurrentSkipList[K, V]) FindLeft(key K) (k K, v V, ok bool) {
+	node := l.lastBefore(func(k K) bool { return l.cmp(k, key) < 0 })
+	if node == nil {
+		return
+	}
+	return node.key, *node.value.Load(), true
+}
+
+// FindRight 返回key右侧（大于key）最近的结点
+func (l *ConcurrentSkipList[K, V]) FindRight(key K) (k K, v V, ok bool) {
+	pred := l.head
+	for layer := concurrentMaxLevel - 1; layer >= 0; layer-- {
+		curr := pred.next[layer].Load()
+		for curr != nil && l.cmp(curr.key, key) <= 0 {
+			pred = curr
+			curr = pred.next[layer].Load()
+		}
+	}
+	if node := l.nextLive(pred); node != nil {
+		return node.key, *node.value.Load(), true
+	}
+	return
+}
+
+// GetMin 获得最小值
+func (l *ConcurrentSkipList[K, V]) GetMin() (k K, v V, ok bool) {
+	for curr := l.head.next[0].Load(); curr != nil; curr = curr.next[0].Load() {
+		if l.live(curr) {
+			return curr.key, *curr.value.Load(), true
+		}
+	}
+	return
+}
+
+// GetMax 获得最大值
+func (l *ConcurrentSkipList[K, V]) GetMax() (k K, v V, ok bool) {
+	node := l.lastBefore(func(K) bool { return true })
+	if node == nil {
+		return
+	}
+	return node.key, *node.value.Load(), true
+}
+
+// Range 按key升序遍历结点，fn 返回false时停止
+// 遍历期间的并发修改可能被看到，也可能看不到
+func (l *ConcurrentSkipList[K, V]) Range(fn func(key K, value V) bool) {
+	for curr := l.head.next[0].Load(); curr != nil; curr = curr.next[0].Load() {
+		if l.live(curr) && !fn(curr.key, *curr.value.Load()) {
+			return
+		}
+	}
+}
+
+// findNode 查找key，填充每层的前驱与后继，返回key所在的最高层，未找到返回-1
+func (l *ConcurrentSkipList[K, V]) findNode(key K, preds, succs *[concurrentMaxLevel]*cnode[K, V]) int {
+	lFound := -1
+	pred := l.head
+	for layer := concurrentMaxLevel - 1; layer >= 0; layer-- {
+		curr := pred.next[layer].Load()
+		for curr != nil && l.cmp(curr.key, key) < 0 {
+			pred = curr
+			curr = pred.next[layer].Load()
+		}
+		if lFound == -1 && curr != nil && l.cmp(curr.key, key) == 0 {
+			lFound = layer
+		}
+		preds[layer] = pred
+		succs[layer] = curr
+	}
+	return lFound
+}
+
+// lastBefore 返回最后一个满足before且未被删除的结点
+// before 对有序的key应当先为true后为false
+func (l *ConcurrentSkipList[K, V]) lastBefore(before func(k K) bool) *cnode[K, V] {
+	pred := l.head
+	for layer := concurrentMaxLevel - 1; layer > 0; layer-- {
+		for curr := pred.next[layer].Load(); curr != nil && before(curr.key); curr = pred.next[layer].Load() {
+			pred = curr
+		}
+	}
+
+	var last *cnode[K, V]
+	start := pred
+	if start != l.head && l.live(start) {
+		last = start
+	}
+	for curr := start.next[0].Load(); curr != nil && before(curr.key); curr = curr.next[0].Load() {
+		if l.live(curr) {
+			last = curr
+		}
+	}
+	if last != nil || start == l.head {
+		return last
+	}
+
+	// 最底层的起点正在被删除，它之前的结点没有遍历到，从头扫描
+	for curr := l.head.next[0].Load(); curr != nil && before(curr.key); curr = curr.next[0].Load() {
+		if l.live(curr) {
+			last = curr
+		}
+	}
+	return last
+}
+
+// lastLess 返回最后一个key小于key的结点，可能是头结点或正在被删除的结点
+func (l *ConcurrentSkipList[K, V]) lastLess(key K) *cnode[K, V] {
+	pred := l.head
+	for layer := concurrentMaxLevel - 1; layer >= 0; layer-- {
+		curr := pred.next[layer].Load()
+		for curr != nil && l.cmp(curr.key, key) < 0 {
+			pred = curr
+			curr = pred.next[layer].Load()
+		}
+	}
+	return pred
+}
+
+// nextLive 返回n之后第一个未被删除的结点
+func (l *ConcurrentSkipList[K, V]) nextLive(n *cnode[K, V]) *cnode[K, V] {
+	for curr := n.next[0].Load(); curr != nil; curr = curr.next[0].Load() {
+		if l.live(curr) {
+			return curr
+		}
+	}
+	return nil
+}
+
+// live 结点已完成插入且未被删除
+func (l *ConcurrentSkipList[K, V]) live(n *cnode[K, V]) bool {
+	return n.fullyLinked.Load() && !n.marked.Load()
+}
+
+// randomLayer 随机生成新结点的最高层，每层晋升概率为1/2
+func (l *ConcurrentSkipList[K, V]) randomLayer() int {
+	layer := 0
+	for layer < concurrentMaxLevel-1 && rand.Int63()&1 == 1 {
+		layer++
+	}
+	return layer
+}
+
+// unlockPreds 释放 [0, highestLocked] 层前驱的锁，同一结点只释放一次
+func unlockPreds[K, V any](preds *[concurrentMaxLevel]*cnode[K, V], highestLocked int) {
+	var prevPred *cnode[K, V]
+	for layer := 0; layer <= highestLocked; layer++ {
+		if preds[layer] != prevPred {
+			preds[layer].mu.Unlock()
+			prevPred = preds[layer]
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package algorithm
+
+import (
+	"cmp"
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+func TestConcurrentSkipList(t *testing.T) {
+	l := NewConcurrent[int, int](cmp.Compare[int])
+	for _, k := range rand.Perm(100) {
+		l.Insert(k*10, k)
+	}
+	l.Insert(500, -1)
+	if l.Len() != 100 {
+		t.Fatalf("len = %d, want 100", l.Len())
+	}
+	if v, ok := l.Get(500); !ok || v != -1 {
+		t.Fatalf("get(500) = %d, %v", v, ok)
+	}
+	if _, ok := l.Get(505); ok {
+		t.Fatal("get absent key")
+	}
+	if k, _, ok := l.FindLeft(500); !ok || k != 490 {
+		t.Fatalf("left of 500 = %d, %v", k, ok)
+	}
+	if k, _, ok := l.FindLeft(505); !ok || k != 500 {
+		t.Fatalf("left of 505 = %d, %v", k, ok)
+	}
+	if k, _, ok := l.FindRight(500); !ok || k != 510 {
+		t.Fatalf("right of 500 = %d, %v", k, ok)
+	}
+	if _, _, ok := l.FindRight(990); ok {
+		t.Fatal("right of max")
+	}
+	if k, _, ok := l.GetMin(); !ok || k != 0 {
+		t.Fatalf("min = %d, %v", k, ok)
+	}
+	if k, _, ok := l.GetMax(); !ok || k != 990 {
+		t.Fatalf("max = %d, %v", k, ok)
+	}
+
+	l.SetMatch(func(target, cur int) bool { return cur-target <= 5 && target-cur <= 5 })
+	if k, _, ok := l.Find(503, 0); !ok || k != 500 {
+		t.Fatalf("find(503) = %d, %v", k, ok)
+	}
+
+	if !l.Delete(500) || l.Delete(500) {
+		t.Fatal("delete 500")
+	}
+	if _, _, ok := l.Find(503, 0); ok {
+		t.Fatal("found deleted key")
+	}
+	prev := -1
+	l.Range(func(k, v int) bool {
+		if k <= prev {
+			t.Fatalf("range out of order: %d after %d", k, prev)
+		}
+		prev = k
+		return true
+	})
+	if l.Len() != 99 {
+		t.Fatalf("len = %d, want 99", l.Len())
+	}
+}
+
+func TestConcurrentSkipListFindSameAsSkipList(t *testing.T) {
+	l := NewOrdered[int, int]()
+	c := NewConcurrent[int, int](cmp.Compare[int])
+	for i := 0; i < 600; i += 3 {
+		l.Insert(i, i)
+		c.Insert(i, i)
+	}
+	var want, got []int
+	match := func(checked *[]int) func(target, cur int) bool {
+		return func(target, cur int) bool {
+			*checked = append(*checked, cur)
+			return cur%50 == 0 && cur < target-100
+		}
+	}
+	l.SetMatch(match(&want))
+	c.SetMatch(match(&got))
+	for _, target := range []int{300, 301, -5, 900} {
+		for _, limit := range []int{-1, 0, 1, 7, 200} {
+			want, got = nil, nil
+			node, ok := l.Find(target, limit)
+			k, _, cok := c.Find(target, limit)
+			if ok != cok || ok && node.GetKey() != k || !equalInts(got, want) {
+				t.Fatalf("Find(%d, %d): concurrent %d %v checked %v, want %v %v checked %v",
+					target, limit, k, cok, got, node.GetKey(), ok, want)
+			}
+		}
+	}
+}
+
+func TestConcurrentSkipListParallel(t *testing.T) {
+	const workers, perWorker = 8, 500
+	l := NewConcurrent[int, int](cmp.Compare[int])
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				key := i*workers + w
+				l.Insert(key, w)
+				if _, ok := l.Get(key); !ok {
+					t.Errorf("key %d missing after insert", key)
+				}
+				l.FindLeft(key)
+				l.FindRight(key)
+				if i%2 == 1 && !l.Delete(key) {
+					t.Errorf("delete %d failed", key)
+				}
+			}
+		}(w)
+	}
+	// 并发读
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				l.Find(rand.Intn(workers*perWorker), 16)
+				l.GetMax()
+			}
+		}()
+	}
+	wg.Wait()
+
+	want := workers * perWorker / 2
+	if int(l.Len()) != want {
+		t.Fatalf("len = %d, want %d", l.Len(), want)
+	}
+	cnt, prev := 0, -1
+	l.Range(func(k, v int) bool {
+		if k <= prev || (k/workers)%2 == 1 {
+			t.Fatalf("unexpected key %d after %d", k, prev)
+		}
+		prev = k
+		cnt++
+		return true
+	})
+	if cnt != want {
+		t.Fatalf("range visited %d, want %d", cnt, want)
+	}
+}
+
+// mutexSkipList 用全局读写锁保护的跳表，作为并发跳表的对照
+type mutexSkipList struct {
+	mu   sync.RWMutex
+	list *SkipList[int, int]
+}
+
+func (m *mutexSkipList) Insert(key, value int) {
+	m.mu.Lock()
+	m.list.Insert(key, value)
+	m.mu.Unlock()
+}
+
+func (m *mutexSkipList) Find(key int) {
+	m.mu.RLock()
+	m.list.Find(key, 0)
+	m.mu.RUnlock()
+}
+
+func BenchmarkConcurrentSkipList(b *testing.B) {
+	l := NewConcurrent[int, int](cmp.Compare[int])
+	for i := 0; i < 100000; i++ {
+		l.Insert(i*2, i)
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		r := rand.New(rand.NewSource(rand.Int63()))
+		for pb.Next() {
+			key := r.Intn(200000)
+			if key%10 == 0 {
+				l.Insert(key, key)
+			} else {
+				l.Get(key)
+			}
+		}
+	})
+}
+
+func BenchmarkMutexSkipList(b *testing.B) {
+	m := &mutexSkipList{list: NewOrdered[int, int]()}
+	for i := 0; i < 100000; i++ {
+		m.Insert(i*2, i)
+	}
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		r := rand.New(rand.NewSource(rand.Int63()))
+		for pb.Next() {
+			key := r.Intn(200000)
+			if key%10 == 0 {
+				m.Insert(key, key)
+			} else {
+				m.Find(key)
+			}
+		}
+	})
+}
@@ -0,0 +1,6 @@
+// Package algorithm 常用的数据结构与算法
+//
+// 跳表 SkipList[K, V] 是泛型实现，需要通过 New 或 NewOrdered 创建，零值不能直接使用。
+// 早期版本中以 Key 接口为索引的非泛型 SkipList 已更名为 KeySkipList，
+// 原来使用 algorithm.SkipList 的代码改为 algorithm.KeySkipList 即可，零值仍然可以直接使用。
+package algorithm
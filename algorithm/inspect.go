@@ -0,0 +1,227 @@
+package algorithm
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrCorrupted 跳表结构不一致
+var ErrCorrupted = errors.New("skiplist: corrupted structure")
+
+// Stats 跳表的结构统计
+type Stats struct {
+	Len      int
+	Height   int // 当前层数
+	MaxLevel int
+	// LevelCounts 每层的结点个数，下标为层数，0 为最底层
+	LevelCounts []int
+	// AvgComparisons 查找已有key平均需要的比较次数
+	AvgComparisons float64
+}
+
+// Stats 统计跳表的结构，计算平均比较次数需要对每个key模拟一次查找，时间复杂度为O(n log n)
+func (l *SkipList[K, V]) Stats() Stats {
+	s := Stats{
+		Len:         int(l.len),
+		Height:      int(l.layer),
+		MaxLevel:    l.opts.maxLevel,
+		LevelCounts: make([]int, l.layer),
+	}
+	total := 0
+	for node := l.head.levels[0].next; node != nil; node = node.levels[0].next {
+		for i := range node.levels {
+			if i < len(s.LevelCounts) {
+				s.LevelCounts[i]++
+			}
+		}
+		total += l.searchCost(node.key)
+	}
+	if l.len > 0 {
+		s.AvgComparisons = float64(total) / float64(l.len)
+	}
+	return s
+}
+
+// searchCost 按 Find 的方式查找key需要的比较次数
+func (l *SkipList[K, V]) searchCost(key K) int {
+	cnt := 0
+	curNode := l.head
+	for i := int(l.layer) - 1; i >= 0; i-- {
+		for next := curNode.levels[i].next; next != nil; next = curNode.levels[i].next {
+			cnt++
+			c := l.cmp(key, next.key)
+			if c == 0 {
+				return cnt
+			}
+			if c < 0 {
+				break
+			}
+			curNode = next
+		}
+	}
+	return cnt
+}
+
+// Validate 检查跳表结构：各层key有序、backward 与最底层的前向指针对称、
+// 各层的结点都在对应高度的塔中、跨度与位置一致、len/layer/tail 与实际结点一致
+func (l *SkipList[K, V]) Validate() error {
+	if l.layer < 0 || int(l.layer) > len(l.head.levels) {
+		return fmt.Errorf("%w: layer %d out of range", ErrCorrupted, l.layer)
+	}
+	if l.layer > 0 && l.head.levels[l.layer-1].next == nil {
+		return fmt.Errorf("%w: top layer %d is empty", ErrCorrupted, l.layer)
+	}
+	for i := int(l.layer); i < len(l.head.levels); i++ {
+		if l.head.levels[i].next != nil {
+			return fmt.Errorf("%w: level %d above layer is linked", ErrCorrupted, i)
+		}
+	}
+
+	// 最底层：顺序、backward、len、tail、结点高度
+	pos := make(map[*Node[K, V]]int, l.len)
+	towers := make([]int, l.layer)
+	var prev *Node[K, V]
+	n := 0
+	for node := l.head.levels[0].next; node != nil; node = node.levels[0].next {
+		n++
+		if node.backward != prev {
+			return fmt.Errorf("%w: backward of node %d does not point to its predecessor", ErrCorrupted, n)
+		}
+		if prev != nil && !l.before(prev.key, node.key) {
+			return fmt.Errorf("%w: keys out of order at node %d", ErrCorrupted, n)
+		}
+		if h := len(node.levels); h == 0 || h > int(l.layer) {
+			return fmt.Errorf("%w: node %d has height %d, layer %d", ErrCorrupted, n, h, l.layer)
+		}
+		for i := range node.levels {
+			towers[i]++
+		}
+		pos[node] = n
+		prev = node
+	}
+	if n != int(l.len) {
+		return fmt.Errorf("%w: bottom level has %d nodes, len = %d", ErrCorrupted, n, l.len)
+	}
+	if l.tail != prev {
+		return fmt.Errorf("%w: tail is not the last node", ErrCorrupted)
+	}
+
+	// 各层：结点属于最底层、高度足够、跨度正确、每个足够高的塔都被链接
+	for i := 0; i < int(l.layer); i++ {
+		cnt, rank := 0, 0
+		for node := l.head; ; {
+			next := node.levels[i].next
+			if next == nil {
+				if node.levels[i].span != n-rank {
+					return fmt.Errorf("%w: level %d last span = %d, want %d", ErrCorrupted, i, node.levels[i].span, n-rank)
+				}
+				break
+			}
+			p, ok := pos[next]
+			if !ok {
+				return fmt.Errorf("%w: level %d links a node missing from the bottom level", ErrCorrupted, i)
+			}
+			if len(next.levels) <= i {
+				return fmt.Errorf("%w: level %d links node %d of height %d", ErrCorrupted, i, p, len(next.levels))
+			}
+			if p <= rank || node.levels[i].span != p-rank {
+				return fmt.Errorf("%w: level %d span to node %d = %d, want %d", ErrCorrupted, i, p, node.levels[i].span, p-rank)
+			}
+			cnt++
+			rank = p
+			node = next
+		}
+		if cnt != towers[i] {
+			return fmt.Errorf("%w: level %d links %d nodes, %d towers reach it", ErrCorrupted, i, cnt, towers[i])
+		}
+	}
+	return nil
+}
+
+// WriteText 以文本格式输出每层的结点，自上而下
+func (l *SkipList[K, V]) WriteText(w io.Writer) error {
+	ew := &errWriter{w: w}
+	fmt.Fprintln(ew, "layer: ", l.layer)
+	fmt.Fprintln(ew, "len: ", l.len)
+	for i := int(l.layer) - 1; i >= 0; i-- {
+		for node := l.head.levels[i].next; node != nil; node = node.levels[i].next {
+			fmt.Fprintf(ew, "[%#v:%#v]", node.key, node.value)
+			if node.levels[i].next != nil {
+				fmt.Fprint(ew, " -->")
+			}
+		}
+		fmt.Fprintln(ew, "---layer:---")
+	}
+	return ew.err
+}
+
+// WriteDOT 以Graphviz DOT格式输出跳表结构，每个结点为一个塔，边上标注跨度
+func (l *SkipList[K, V]) WriteDOT(w io.Writer) error {
+	ew := &errWriter{w: w}
+	fmt.Fprintln(ew, "digraph skiplist {")
+	fmt.Fprintln(ew, "\trankdir=LR;")
+	fmt.Fprintln(ew, "\tnode [shape=record];")
+
+	ids := make(map[*Node[K, V]]int, l.len)
+	fmt.Fprintf(ew, "\tn0 [label=\"%s\"];\n", towerLabel(int(l.layer), "head"))
+	i := 0
+	for node := l.head.levels[0].next; node != nil; node = node.levels[0].next {
+		i++
+		ids[node] = i
+		label := dotEscape(fmt.Sprintf("%v: %v", node.key, node.value))
+		fmt.Fprintf(ew, "\tn%d [label=\"%s\"];\n", i, towerLabel(len(node.levels), label))
+	}
+	for level := 0; level < int(l.layer); level++ {
+		for node := l.head; node.levels[level].next != nil; node = node.levels[level].next {
+			next := node.levels[level].next
+			fmt.Fprintf(ew, "\tn%d:l%d -> n%d:l%d [label=\"%d\"];\n", ids[node], level, ids[next], level, node.levels[level].span)
+		}
+	}
+	fmt.Fprintln(ew, "}")
+	return ew.err
+}
+
+// towerLabel 生成塔的record标签，自上而下为各层的端口，最下面为结点内容
+func towerLabel(height int, content string) string {
+	var b strings.Builder
+	b.WriteString("{")
+	for i := height - 1; i >= 0; i-- {
+		fmt.Fprintf(&b, "<l%d> L%d|", i, i)
+	}
+	b.WriteString(content)
+	b.WriteString("}")
+	return b.String()
+}
+
+// dotEscape 转义record标签中的特殊字符
+func dotEscape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '"', '\\', '{', '}', '|', '<', '>':
+			b.WriteByte('\\')
+		case '\n':
+			b.WriteString(`\n`)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// errWriter 记录第一次写入错误，之后的写入直接忽略
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (ew *errWriter) Write(p []byte) (int, error) {
+	if ew.err != nil {
+		return 0, ew.err
+	}
+	n, err := ew.w.Write(p)
+	ew.err = err
+	return n, err
+}
@@ -0,0 +1,121 @@
+package algorithm
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func TestSkipListStats(t *testing.T) {
+	l := NewOrdered[int, int]()
+	keys := make([]int, 1024)
+	for i := range keys {
+		keys[i] = i
+	}
+	if err := l.FromSorted(keys, keys); err != nil {
+		t.Fatal(err)
+	}
+	s := l.Stats()
+	if s.Len != 1024 || s.Height != 11 || s.MaxLevel != DefaultMaxLevel {
+		t.Fatalf("stats = %+v", s)
+	}
+	for i, cnt := range s.LevelCounts {
+		if cnt != 1024>>i {
+			t.Fatalf("level %d has %d nodes, want %d", i, cnt, 1024>>i)
+		}
+	}
+	if s.AvgComparisons < 5 || s.AvgComparisons > 25 {
+		t.Fatalf("avg comparisons = %f", s.AvgComparisons)
+	}
+	if s := NewOrdered[int, int]().Stats(); s.Len != 0 || s.AvgComparisons != 0 || len(s.LevelCounts) != 0 {
+		t.Fatalf("empty stats = %+v", s)
+	}
+}
+
+func TestSkipListValidate(t *testing.T) {
+	build := func() *SkipList[int, int] {
+		l := NewOrdered[int, int](WithRandSource(rand.NewSource(3)))
+		for _, k := range rand.Perm(200) {
+			l.Insert(k, k)
+		}
+		return l
+	}
+	l := build()
+	if err := l.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	l.DeleteRange(50, 100)
+	l.Split(150)
+	if err := l.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	corruptions := map[string]func(l *SkipList[int, int]){
+		"len":      func(l *SkipList[int, int]) { l.len++ },
+		"layer":    func(l *SkipList[int, int]) { l.layer++ },
+		"tail":     func(l *SkipList[int, int]) { l.tail = l.head.levels[0].next },
+		"backward": func(l *SkipList[int, int]) { l.tail.backward = nil },
+		"order": func(l *SkipList[int, int]) {
+			first := l.head.levels[0].next
+			first.key, first.levels[0].next.key = first.levels[0].next.key, first.key
+		},
+		"span": func(l *SkipList[int, int]) { l.head.levels[l.layer-1].span++ },
+		"tower": func(l *SkipList[int, int]) {
+			// 在第二层跳过一个结点
+			node := l.head.levels[1].next
+			l.head.levels[1].span += node.levels[1].span
+			l.head.levels[1].next = node.levels[1].next
+		},
+	}
+	for name, corrupt := range corruptions {
+		l := build()
+		corrupt(l)
+		if err := l.Validate(); !errors.Is(err, ErrCorrupted) {
+			t.Fatalf("%s corruption: err = %v", name, err)
+		}
+	}
+}
+
+func TestSkipListWrite(t *testing.T) {
+	l := NewOrdered[string, int]()
+	if err := l.FromSorted([]string{"a", "b", "c|d"}, []int{1, 2, 3}); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := l.WriteText(&buf); err != nil {
+		t.Fatal(err)
+	}
+	want := "layer:  2\nlen:  3\n[\"b\":2]---layer:---\n[\"a\":1] -->[\"b\":2] -->[\"c|d\":3]---layer:---\n"
+	if buf.String() != want {
+		t.Fatalf("text = %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	if err := l.WriteDOT(&buf); err != nil {
+		t.Fatal(err)
+	}
+	dot := buf.String()
+	for _, s := range []string{
+		"digraph skiplist {",
+		`n3 [label="{<l0> L0|c\|d: 3}"];`,
+		`n0:l1 -> n2:l1 [label="2"];`,
+		`n2:l0 -> n3:l0 [label="1"];`,
+	} {
+		if !strings.Contains(dot, s) {
+			t.Fatalf("dot output missing %q:\n%s", s, dot)
+		}
+	}
+
+	if err := l.WriteDOT(failWriter{}); err == nil {
+		t.Fatal("write error not returned")
+	}
+}
+
+type failWriter struct{}
+
+func (failWriter) Write([]byte) (int, error) {
+	return 0, errors.New("write failed")
+}
@@ -0,0 +1,70 @@
+package algorithm
+
+import "iter"
+
+// Seek 返回第一个key大于等于给定key的结点，可以继续用 Next/Prev 移动
+func (l *SkipList[K, V]) Seek(key K) (*Node[K, V], bool) {
+	node := l.seek(key)
+	return node, node != nil
+}
+
+// RangeByKey 返回key在lo和hi之间的结点，loInclusive/hiInclusive 表示是否包含边界
+func (l *SkipList[K, V]) RangeByKey(lo, hi K, loInclusive, hiInclusive bool) []*Node[K, V] {
+	var ret []*Node[K, V]
+	var node *Node[K, V]
+	if loInclusive {
+		node, _ = l.Ceiling(lo)
+	} else {
+		// multimap模式下跳过所有等于lo的结点
+		node, _ = l.Higher(lo)
+	}
+	for ; node != nil; node = node.levels[0].next {
+		c := l.cmp(node.key, hi)
+		if c > 0 || c == 0 && !hiInclusive {
+			break
+		}
+		ret = append(ret, node)
+	}
+	return ret
+}
+
+// All 按key升序遍历所有结点
+func (l *SkipList[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		node, _ := l.GetMin()
+		for ; node != nil; node = node.levels[0].next {
+			if !yield(node.key, node.value) {
+				return
+			}
+		}
+	}
+}
+
+// Backward 按key降序遍历所有结点
+func (l *SkipList[K, V]) Backward() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		node, _ := l.GetMax()
+		for ; node != nil; node = node.backward {
+			if !yield(node.key, node.value) {
+				return
+			}
+		}
+	}
+}
+
+// Range 按key升序遍历 [lo, hi) 内的结点，遍历过程不分配内存
+func (l *SkipList[K, V]) Range(lo, hi K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for node := l.seek(lo); node != nil && l.cmp(node.key, hi) < 0; node = node.levels[0].next {
+			if !yield(node.key, node.value) {
+				return
+			}
+		}
+	}
+}
+
+// seek 返回最底层第一个key大于等于key的结点
+func (l *SkipList[K, V]) seek(key K) *Node[K, V] {
+	pre, _ := l.lowerPre(key)
+	return pre.levels[0].next
+}
@@ -0,0 +1,143 @@
+package algorithm
+
+import (
+	"testing"
+)
+
+func TestSkipListSeek(t *testing.T) {
+	l := NewOrdered[int, int]()
+	for i := 0; i < 100; i += 10 {
+		l.Insert(i, i)
+	}
+
+	node, ok := l.Seek(35)
+	if !ok || node.GetKey() != 40 {
+		t.Fatalf("seek(35) = %v, %v", node.GetKey(), ok)
+	}
+	next, ok := node.Next()
+	if !ok || next.GetKey() != 50 {
+		t.Fatalf("next = %v, %v", next.GetKey(), ok)
+	}
+	prev, ok := node.Prev()
+	if !ok || prev.GetKey() != 30 {
+		t.Fatalf("prev = %v, %v", prev.GetKey(), ok)
+	}
+	if node, ok = l.Seek(40); !ok || node.GetKey() != 40 {
+		t.Fatalf("seek(40) = %v, %v", node.GetKey(), ok)
+	}
+	if _, ok = l.Seek(91); ok {
+		t.Fatal("seek past max")
+	}
+	first, _ := l.Seek(-1)
+	if _, ok = first.Prev(); ok {
+		t.Fatal("prev of min")
+	}
+}
+
+func TestSkipListRangeByKey(t *testing.T) {
+	l := NewOrdered[int, int]()
+	for i := 0; i < 10; i++ {
+		l.Insert(i, i)
+	}
+	tests := []struct {
+		lo, hi       int
+		loInc, hiInc bool
+		want         []int
+	}{
+		{2, 5, true, true, []int{2, 3, 4, 5}},
+		{2, 5, false, false, []int{3, 4}},
+		{2, 5, true, false, []int{2, 3, 4}},
+		{-5, 1, false, true, []int{0, 1}},
+		{8, 20, false, true, []int{9}},
+		{5, 5, true, true, []int{5}},
+		{5, 5, true, false, nil},
+	}
+	for _, tt := range tests {
+		nodes := l.RangeByKey(tt.lo, tt.hi, tt.loInc, tt.hiInc)
+		var got []int
+		for _, node := range nodes {
+			got = append(got, node.GetKey())
+		}
+		if !equalInts(got, tt.want) {
+			t.Fatalf("range(%d, %d, %v, %v) = %v, want %v", tt.lo, tt.hi, tt.loInc, tt.hiInc, got, tt.want)
+		}
+	}
+
+	// multimap模式下不包含下界时跳过所有相等的key
+	dup := NewOrdered[int, int](WithDuplicates())
+	for _, k := range []int{1, 2, 2, 2, 3} {
+		dup.Insert(k, k)
+	}
+	if got := len(dup.RangeByKey(2, 3, false, true)); got != 1 {
+		t.Fatalf("exclusive range in multimap = %d nodes, want 1", got)
+	}
+}
+
+func TestSkipListIterators(t *testing.T) {
+	l := NewOrdered[int, int]()
+	for i := 9; i >= 0; i-- {
+		l.Insert(i, i*i)
+	}
+
+	var got []int
+	for k, v := range l.All() {
+		if v != k*k {
+			t.Fatalf("value of %d = %d", k, v)
+		}
+		got = append(got, k)
+	}
+	if !equalInts(got, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}) {
+		t.Fatalf("all = %v", got)
+	}
+
+	got = got[:0]
+	for k := range l.Backward() {
+		if k < 7 {
+			break
+		}
+		got = append(got, k)
+	}
+	if !equalInts(got, []int{9, 8, 7}) {
+		t.Fatalf("backward = %v", got)
+	}
+
+	got = got[:0]
+	for k := range l.Range(3, 6) {
+		got = append(got, k)
+	}
+	if !equalInts(got, []int{3, 4, 5}) {
+		t.Fatalf("range = %v", got)
+	}
+
+	for range NewOrdered[int, int]().All() {
+		t.Fatal("iterate empty list")
+	}
+}
+
+func TestSkipListRangeAllocs(t *testing.T) {
+	l := NewOrdered[int, int]()
+	for i := 0; i < 1000; i++ {
+		l.Insert(i, i)
+	}
+	allocs := testing.AllocsPerRun(10, func() {
+		sum := 0
+		for _, v := range l.Range(100, 900) {
+			sum += v
+		}
+	})
+	if allocs > 1 {
+		t.Fatalf("range allocs = %v", allocs)
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,138 @@
+package algorithm
+
+// Key 索引
+type Key interface {
+	Equal(then Key) bool
+	Less(then Key) bool
+	Check(then Key) bool
+}
+
+// KeyNode 以 Key 为索引的跳表结点
+type KeyNode = Node[Key, interface{}]
+
+// KeySkipList 以 Key 接口为索引的跳表，兼容旧的调用方式，即原来的非泛型 SkipList
+// 零值可以直接使用
+type KeySkipList struct {
+	list *SkipList[Key, interface{}]
+}
+
+// NewKeySkipList 创建以 Key 接口为索引的跳表
+func NewKeySkipList() *KeySkipList {
+	l := &KeySkipList{}
+	l.init()
+	return l
+}
+
+// compareKey 将 Key 接口的 Equal/Less 转换为比较函数
+func compareKey(a, b Key) int {
+	if a.Equal(b) {
+		return 0
+	}
+	if a.Less(b) {
+		return -1
+	}
+	return 1
+}
+
+// checkKey 将 Key 接口的 Check 转换为匹配函数
+func checkKey(target, cur Key) bool {
+	return target.Check(cur)
+}
+
+func (l *KeySkipList) init() *SkipList[Key, interface{}] {
+	if l.list == nil {
+		l.list = New[Key, interface{}](compareKey)
+		l.list.SetMatch(checkKey)
+	}
+	return l.list
+}
+
+// Print 打印整个跳表
+func (l *KeySkipList) Print() {
+	l.init().Print()
+}
+
+// Stats 统计跳表的结构
+func (l *KeySkipList) Stats() Stats {
+	return l.init().Stats()
+}
+
+// Validate 检查跳表结构
+func (l *KeySkipList) Validate() error {
+	return l.init().Validate()
+}
+
+// Len 只输出底层的结点个数
+func (l *KeySkipList) Len() int32 {
+	return l.init().Len()
+}
+
+// Sort 将最底层的链表转化为切片输出
+func (l *KeySkipList) Sort() []interface{} {
+	return l.init().Sort()
+}
+
+// GetMin 获得最小值
+func (l *KeySkipList) GetMin() (*KeyNode, bool) {
+	return l.init().GetMin()
+}
+
+// GetMax 获得最大值
+func (l *KeySkipList) GetMax() (*KeyNode, bool) {
+	return l.init().GetMax()
+}
+
+// Insert 插入节点
+func (l *KeySkipList) Insert(key Key, value interface{}) {
+	l.init().Insert(key, value)
+}
+
+// Delete 删除结点
+func (l *KeySkipList) Delete(tarKey Key) {
+	l.init().Delete(tarKey)
+}
+
+// Find 查找结点，key相等的结点优先返回，否则从key所在的位置向两侧交替查找 Check 为true的结点
+// searchLimit 为最多调用 Check 的次数（旧版为比较次数）；0 与旧版相同表示不限制，
+// 不使用 SkipList.Find 的 DefaultSearchLimit，负数同样表示不限制
+func (l *KeySkipList) Find(key Key, searchLimit int) (*KeyNode, bool) {
+	if searchLimit == 0 {
+		searchLimit = -1
+	}
+	return l.init().Find(key, searchLimit)
+}
+
+// FindLeft 返回左侧结点
+func (l *KeySkipList) FindLeft(key Key) (*KeyNode, bool) {
+	return l.init().FindLeft(key)
+}
+
+// FindRight 返回右侧结点
+func (l *KeySkipList) FindRight(key Key) (*KeyNode, bool) {
+	return l.init().FindRight(key)
+}
+
+// Floor 返回最后一个小于等于key的结点
+func (l *KeySkipList) Floor(key Key) (*KeyNode, bool) {
+	return l.init().Floor(key)
+}
+
+// Ceiling 返回第一个大于等于key的结点
+func (l *KeySkipList) Ceiling(key Key) (*KeyNode, bool) {
+	return l.init().Ceiling(key)
+}
+
+// Lower 返回最后一个严格小于key的结点
+func (l *KeySkipList) Lower(key Key) (*KeyNode, bool) {
+	return l.init().Lower(key)
+}
+
+// Higher 返回第一个严格大于key的结点
+func (l *KeySkipList) Higher(key Key) (*KeyNode, bool) {
+	return l.init().Higher(key)
+}
+
+// ClearAll 清空节点
+func (l *KeySkipList) ClearAll() {
+	l.init().ClearAll()
+}
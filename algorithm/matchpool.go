@@ -0,0 +1,281 @@
+package algorithm
+
+import (
+	"cmp"
+	"container/list"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Clock 时钟，测试时可以注入固定的时间
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// SystemClock 系统时钟
+var SystemClock Clock = systemClock{}
+
+var (
+	// ErrMatchDuplicate 玩家已在匹配池中
+	ErrMatchDuplicate = errors.New("matchpool: entry already waiting")
+)
+
+// MatchEntry 匹配池中等待的玩家
+type MatchEntry[T any] struct {
+	ID       int64
+	Rating   int64
+	Value    T
+	JoinedAt time.Time
+}
+
+// MatchConfig 匹配池配置
+type MatchConfig[T any] struct {
+	// TeamSize 每局人数，小于2时按2处理
+	TeamSize int
+	// BaseTolerance 刚加入时可接受的分差
+	BaseTolerance int64
+	// WidenStep 每等待 WidenInterval 可接受的分差增加 WidenStep
+	WidenStep     int64
+	WidenInterval time.Duration
+	// MaxTolerance 可接受分差的上限，0 表示不限制
+	MaxTolerance int64
+	// Timeout 最长等待时间，超时后移出匹配池，0 表示不超时
+	Timeout time.Duration
+	// SearchLimit 每次查找最多检查的候选人数，0 表示使用 DefaultSearchLimit，负数表示不限制
+	SearchLimit int
+	// Clock 时钟，默认为系统时钟
+	Clock Clock
+	// OnMatch 匹配成功回调，entries 第一个为发起匹配的玩家
+	OnMatch func(entries []MatchEntry[T])
+	// OnTimeout 等待超时回调
+	OnTimeout func(entry MatchEntry[T])
+}
+
+// matchKey 匹配池跳表的索引，按分数排序，分数相同按ID排序
+type matchKey struct {
+	rating int64
+	id     int64
+}
+
+func compareMatchKey(a, b matchKey) int {
+	if c := cmp.Compare(a.rating, b.rating); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.id, b.id)
+}
+
+type waitEntry[T any] struct {
+	MatchEntry[T]
+	elem *list.Element
+}
+
+// MatchPool 匹配池
+// 等待的玩家按分数存放在跳表中，可接受的分差随等待时间放宽，
+// 调用 Tick 时按等待时间从长到短依次为玩家撮合
+type MatchPool[T any] struct {
+	mu      sync.Mutex
+	cfg     MatchConfig[T]
+	list    *SkipList[matchKey, *waitEntry[T]]
+	entries map[int64]*waitEntry[T]
+	queue   *list.List      // 按加入时间排列的等待队列
+	now     time.Time       // 本轮撮合的时间
+	team    []*waitEntry[T] // 正在撮合的队伍，新成员需要与所有成员匹配
+}
+
+// NewMatchPool 创建匹配池
+func NewMatchPool[T any](cfg MatchConfig[T]) *MatchPool[T] {
+	if cfg.TeamSize < 2 {
+		cfg.TeamSize = 2
+	}
+	if cfg.Clock == nil {
+		cfg.Clock = SystemClock
+	}
+	p := &MatchPool[T]{
+		cfg:     cfg,
+		list:    New[matchKey, *waitEntry[T]](compareMatchKey),
+		entries: make(map[int64]*waitEntry[T]),
+		queue:   list.New(),
+	}
+	p.list.SetMatch(p.check)
+	return p
+}
+
+// Join 加入匹配池
+func (p *MatchPool[T]) Join(id, rating int64, value T) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, exist := p.entries[id]; exist {
+		return ErrMatchDuplicate
+	}
+	e := &waitEntry[T]{MatchEntry: MatchEntry[T]{ID: id, Rating: rating, Value: value, JoinedAt: p.cfg.Clock.Now()}}
+	e.elem = p.queue.PushBack(e)
+	p.entries[id] = e
+	p.list.Insert(matchKey{rating: rating, id: id}, e)
+	return nil
+}
+
+// Leave 离开匹配池，返回玩家是否在等待
+func (p *MatchPool[T]) Leave(id int64) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	e, exist := p.entries[id]
+	if !exist {
+		return false
+	}
+	p.remove(e)
+	return true
+}
+
+// Len 等待的玩家数
+func (p *MatchPool[T]) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.entries)
+}
+
+// Tolerance 返回玩家当前可接受的分差
+func (p *MatchPool[T]) Tolerance(id int64) (int64, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	e, exist := p.entries[id]
+	if !exist {
+		return 0, false
+	}
+	return p.tolerance(e, p.cfg.Clock.Now()), true
+}
+
+// Tick 进行一轮撮合：先移除超时的玩家，再按等待时间从长到短撮合
+// 返回本轮匹配成功的局数，回调在释放锁之后执行
+func (p *MatchPool[T]) Tick() int {
+	p.mu.Lock()
+	p.now = p.cfg.Clock.Now()
+
+	var timeouts []MatchEntry[T]
+	if p.cfg.Timeout > 0 {
+		for elem := p.queue.Front(); elem != nil; {
+			e := elem.Value.(*waitEntry[T])
+			elem = elem.Next()
+			if p.now.Sub(e.JoinedAt) < p.cfg.Timeout {
+				// 队列按加入时间排列，后面的都未超时
+				break
+			}
+			p.remove(e)
+			timeouts = append(timeouts, e.MatchEntry)
+		}
+	}
+
+	waiting := make([]*waitEntry[T], 0, p.queue.Len())
+	for elem := p.queue.Front(); elem != nil; elem = elem.Next() {
+		waiting = append(waiting, elem.Value.(*waitEntry[T]))
+	}
+	var matches [][]MatchEntry[T]
+	for _, e := range waiting {
+		if p.entries[e.ID] != e {
+			// 已经作为其他人的队友被撮合
+			continue
+		}
+		if team := p.matchTeam(e); team != nil {
+			matches = append(matches, team)
+		}
+	}
+	p.mu.Unlock()
+
+	if p.cfg.OnTimeout != nil {
+		for _, e := range timeouts {
+			p.cfg.OnTimeout(e)
+		}
+	}
+	if p.cfg.OnMatch != nil {
+		for _, team := range matches {
+			p.cfg.OnMatch(team)
+		}
+	}
+	return len(matches)
+}
+
+// matchTeam 为anchor撮合一局，成功时将所有成员移出匹配池
+func (p *MatchPool[T]) matchTeam(anchor *waitEntry[T]) []MatchEntry[T] {
+	key := matchKey{rating: anchor.Rating, id: anchor.ID}
+	// 先把发起者移出跳表，避免匹配到自己；凑不满一局时再放回
+	p.list.Delete(key)
+	team := []*waitEntry[T]{anchor}
+	defer func() { p.team = nil }()
+	for len(team) < p.cfg.TeamSize {
+		p.team = team
+		node, ok := p.list.Find(key, p.cfg.SearchLimit)
+		if !ok {
+			break
+		}
+		member := node.GetValue()
+		p.list.Delete(matchKey{rating: member.Rating, id: member.ID})
+		team = append(team, member)
+	}
+
+	if len(team) < p.cfg.TeamSize {
+		for _, e := range team {
+			p.list.Insert(matchKey{rating: e.Rating, id: e.ID}, e)
+		}
+		return nil
+	}
+
+	ret := make([]MatchEntry[T], 0, len(team))
+	for _, e := range team {
+		p.queue.Remove(e.elem)
+		delete(p.entries, e.ID)
+		ret = append(ret, e.MatchEntry)
+	}
+	return ret
+}
+
+// check 跳表的匹配函数：cur 与正在撮合的队伍中每个成员的分差都在双方可接受的范围内
+func (p *MatchPool[T]) check(target, cur matchKey) bool {
+	if target.id == cur.id {
+		return false
+	}
+	a, b := p.entries[target.id], p.entries[cur.id]
+	if a == nil || b == nil || !p.compatible(a, b) {
+		return false
+	}
+	for _, member := range p.team {
+		if member != a && !p.compatible(member, b) {
+			return false
+		}
+	}
+	return true
+}
+
+// compatible 双方的分差都在对方可接受的范围内
+func (p *MatchPool[T]) compatible(a, b *waitEntry[T]) bool {
+	diff := a.Rating - b.Rating
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff <= p.tolerance(a, p.now) && diff <= p.tolerance(b, p.now)
+}
+
+// tolerance 玩家在now时刻可接受的分差
+func (p *MatchPool[T]) tolerance(e *waitEntry[T], now time.Time) int64 {
+	tol := p.cfg.BaseTolerance
+	if p.cfg.WidenInterval > 0 {
+		if waited := now.Sub(e.JoinedAt); waited > 0 {
+			tol += p.cfg.WidenStep * int64(waited/p.cfg.WidenInterval)
+		}
+	}
+	if p.cfg.MaxTolerance > 0 && tol > p.cfg.MaxTolerance {
+		tol = p.cfg.MaxTolerance
+	}
+	return tol
+}
+
+func (p *MatchPool[T]) remove(e *waitEntry[T]) {
+	p.list.Delete(matchKey{rating: e.Rating, id: e.ID})
+	p.queue.Remove(e.elem)
+	delete(p.entries, e.ID)
+}
@@ -0,0 +1,145 @@
+package algorithm
+
+import (
+	"testing"
+	"time"
+)
+
+// fakeClock 测试用的时钟
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func TestMatchPoolWidening(t *testing.T) {
+	clock := newFakeClock()
+	var matched [][]MatchEntry[string]
+	p := NewMatchPool(MatchConfig[string]{
+		BaseTolerance: 50,
+		WidenStep:     50,
+		WidenInterval: 10 * time.Second,
+		MaxTolerance:  200,
+		Clock:         clock,
+		OnMatch:       func(entries []MatchEntry[string]) { matched = append(matched, entries) },
+	})
+
+	p.Join(1, 1000, "a")
+	p.Join(2, 1120, "b")
+	p.Join(3, 2000, "c")
+	if err := p.Join(1, 1000, "a"); err != ErrMatchDuplicate {
+		t.Fatalf("join twice err = %v", err)
+	}
+
+	if n := p.Tick(); n != 0 || p.Len() != 3 {
+		t.Fatalf("tick matched %d, len %d", n, p.Len())
+	}
+
+	clock.Advance(20 * time.Second)
+	if tol, _ := p.Tolerance(1); tol != 150 {
+		t.Fatalf("tolerance = %d, want 150", tol)
+	}
+	if n := p.Tick(); n != 1 {
+		t.Fatalf("tick matched %d, want 1", n)
+	}
+	if len(matched) != 1 || matched[0][0].ID != 1 || matched[0][1].ID != 2 {
+		t.Fatalf("matched = %+v", matched)
+	}
+	if p.Len() != 1 {
+		t.Fatalf("len = %d, want 1", p.Len())
+	}
+
+	// 分差超过上限，永远不会匹配
+	p.Join(4, 2300, "d")
+	clock.Advance(time.Hour)
+	if n := p.Tick(); n != 0 {
+		t.Fatalf("matched beyond max tolerance")
+	}
+}
+
+func TestMatchPoolTeamAndTimeout(t *testing.T) {
+	clock := newFakeClock()
+	var matched [][]MatchEntry[int]
+	var timeouts []int64
+	p := NewMatchPool(MatchConfig[int]{
+		TeamSize:      3,
+		BaseTolerance: 100,
+		Timeout:       time.Minute,
+		Clock:         clock,
+		OnMatch:       func(entries []MatchEntry[int]) { matched = append(matched, entries) },
+		OnTimeout:     func(entry MatchEntry[int]) { timeouts = append(timeouts, entry.ID) },
+	})
+
+	p.Join(1, 1000, 0)
+	p.Join(2, 1050, 0)
+	clock.Advance(30 * time.Second)
+	p.Join(3, 3000, 0)
+	if n := p.Tick(); n != 0 {
+		t.Fatal("matched incomplete team")
+	}
+	p.Join(4, 950, 0)
+	if n := p.Tick(); n != 1 {
+		t.Fatalf("tick matched %d, want 1", n)
+	}
+	if len(matched[0]) != 3 || matched[0][0].ID != 1 {
+		t.Fatalf("team = %+v", matched[0])
+	}
+
+	clock.Advance(40 * time.Second)
+	p.Tick()
+	if len(timeouts) != 0 {
+		t.Fatalf("timeout too early: %v", timeouts)
+	}
+	clock.Advance(30 * time.Second)
+	p.Tick()
+	if len(timeouts) != 1 || timeouts[0] != 3 || p.Len() != 0 {
+		t.Fatalf("timeouts = %v, len = %d", timeouts, p.Len())
+	}
+
+	p.Join(5, 1000, 0)
+	if !p.Leave(5) || p.Leave(5) || p.Len() != 0 {
+		t.Fatal("leave")
+	}
+}
+
+func TestMatchPoolTeamPairwise(t *testing.T) {
+	var matched [][]MatchEntry[int]
+	p := NewMatchPool(MatchConfig[int]{
+		TeamSize:      3,
+		BaseTolerance: 100,
+		Clock:         newFakeClock(),
+		OnMatch:       func(entries []MatchEntry[int]) { matched = append(matched, entries) },
+	})
+	// 2 和 3 都在 1 的容忍范围内，但彼此相差180
+	p.Join(1, 1000, 0)
+	p.Join(2, 1090, 0)
+	p.Join(3, 910, 0)
+	if n := p.Tick(); n != 0 {
+		t.Fatalf("matched %+v", matched)
+	}
+	p.Join(4, 1050, 0)
+	if n := p.Tick(); n != 1 {
+		t.Fatalf("tick matched %d, want 1", n)
+	}
+	team := matched[0]
+	for i := range team {
+		for j := range team {
+			if d := team[i].Rating - team[j].Rating; d > 100 {
+				t.Fatalf("team %+v: %d and %d differ by %d", team, team[i].ID, team[j].ID, d)
+			}
+		}
+	}
+	if p.Len() != 1 {
+		t.Fatalf("len = %d, want 1", p.Len())
+	}
+}
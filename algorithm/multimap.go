@@ -0,0 +1,30 @@
+package algorithm
+
+// FindAll 返回与key相等的所有结点的value，按插入顺序排列
+func (l *SkipList[K, V]) FindAll(key K) []V {
+	var ret []V
+	for node := l.seek(key); node != nil && l.cmp(node.key, key) == 0; node = node.levels[0].next {
+		ret = append(ret, node.value)
+	}
+	return ret
+}
+
+// Count 返回与key相等的结点个数
+func (l *SkipList[K, V]) Count(key K) int {
+	_, lo := l.lowerPre(key)
+	_, hi := l.upperPre(key)
+	return hi - lo
+}
+
+// DeleteOne 删除与key相等且value满足matcher的第一个结点，返回是否删除
+func (l *SkipList[K, V]) DeleteOne(key K, matcher func(value V) bool) bool {
+	pre, rank := l.lowerPre(key)
+	for node := pre.levels[0].next; node != nil && l.cmp(node.key, key) == 0; node = node.levels[0].next {
+		rank++
+		if matcher(node.value) {
+			l.removeAt(rank)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+package algorithm
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestSkipListDuplicates(t *testing.T) {
+	l := NewOrdered[int, string](WithDuplicates())
+	l.Insert(1, "a")
+	l.Insert(2, "b")
+	l.Insert(1, "c")
+	l.Insert(0, "d")
+	l.Insert(1, "e")
+	checkSpans(t, l)
+
+	if l.Len() != 5 || l.Count(1) != 3 || l.Count(3) != 0 {
+		t.Fatalf("len = %d, count(1) = %d", l.Len(), l.Count(1))
+	}
+	if got := l.FindAll(1); len(got) != 3 || got[0] != "a" || got[1] != "c" || got[2] != "e" {
+		t.Fatalf("findall = %v", got)
+	}
+	if node, ok := l.Find(1, 0); !ok || node.GetValue() != "a" {
+		t.Fatalf("find = %v, %v", node.GetValue(), ok)
+	}
+	if rank, _ := l.Rank(1); rank != 1 {
+		t.Fatalf("rank = %d, want 1", rank)
+	}
+	if node, _ := l.FindLeft(1); node.GetValue() != "d" {
+		t.Fatalf("left = %v", node.GetValue())
+	}
+	if node, _ := l.FindRight(1); node.GetValue() != "b" {
+		t.Fatalf("right = %v", node.GetValue())
+	}
+
+	if !l.DeleteOne(1, func(v string) bool { return v == "c" }) {
+		t.Fatal("deleteone c")
+	}
+	if l.DeleteOne(1, func(v string) bool { return v == "b" }) {
+		t.Fatal("deleteone matched other key")
+	}
+	checkSpans(t, l)
+	l.Delete(1)
+	checkSpans(t, l)
+	if got := l.FindAll(1); len(got) != 1 || got[0] != "e" {
+		t.Fatalf("findall = %v", got)
+	}
+	if got := l.Sort(); len(got) != 3 || got[0] != "d" || got[1] != "e" || got[2] != "b" {
+		t.Fatalf("sort = %v", got)
+	}
+}
+
+func TestSkipListDuplicatesRandom(t *testing.T) {
+	l := NewOrdered[int, int](WithDuplicates())
+	counts := map[int]int{}
+	for i := 0; i < 2000; i++ {
+		k := rand.Intn(50)
+		if rand.Intn(3) == 0 {
+			l.Delete(k)
+			if counts[k] > 0 {
+				counts[k]--
+			}
+		} else {
+			l.Insert(k, i)
+			counts[k]++
+		}
+	}
+	checkSpans(t, l)
+	total := 0
+	for k, c := range counts {
+		if l.Count(k) != c {
+			t.Fatalf("count(%d) = %d, want %d", k, l.Count(k), c)
+		}
+		// 相等的key保持插入顺序
+		vs := l.FindAll(k)
+		for i := 1; i < len(vs); i++ {
+			if vs[i] < vs[i-1] {
+				t.Fatalf("values of %d out of order: %v", k, vs)
+			}
+		}
+		total += c
+	}
+	if int(l.Len()) != total {
+		t.Fatalf("len = %d, want %d", l.Len(), total)
+	}
+}
@@ -0,0 +1,126 @@
+package algorithm
+
+import "sync/atomic"
+
+// ChangeKind 跳表修改的类型
+type ChangeKind uint8
+
+const (
+	// ChangeInsert 插入新结点
+	ChangeInsert ChangeKind = iota + 1
+	// ChangeUpdate 覆盖已有结点的value
+	ChangeUpdate
+	// ChangeDelete 删除结点
+	ChangeDelete
+	// ChangeReset 批量重建（ClearAll、FromSorted、ReadFrom、Split、Merge 首尾相接），
+	// 不再逐个通知结点的变化，观察者需要重新读取整个跳表
+	ChangeReset
+)
+
+// Change 跳表的一次修改
+// 排名从0开始，OldRank 为修改前的排名，NewRank 为修改后的排名，不存在时为-1
+type Change[K, V any] struct {
+	Kind     ChangeKind
+	Key      K
+	OldValue V
+	NewValue V
+	OldRank  int
+	NewRank  int
+}
+
+type observer[K, V any] struct {
+	fn func(Change[K, V])
+}
+
+// Observe 注册观察者，每次修改后在修改的goroutine中同步调用fn，返回取消注册的函数
+// fn 中不能修改跳表
+func (l *SkipList[K, V]) Observe(fn func(Change[K, V])) (cancel func()) {
+	o := &observer[K, V]{fn: fn}
+	l.observers = append(l.observers, o)
+	return func() {
+		for i, cur := range l.observers {
+			if cur == o {
+				l.observers = append(l.observers[:i:i], l.observers[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Subscription 修改通知的订阅
+type Subscription[K, V any] struct {
+	// C 接收修改通知，Close 之后被关闭
+	C       <-chan Change[K, V]
+	ch      chan Change[K, V]
+	dropped atomic.Int64
+	cancel  func()
+}
+
+// Subscribe 订阅修改通知，buf 为通道的缓冲大小
+// 发送不会阻塞修改操作，通道已满时丢弃通知并计数
+func (l *SkipList[K, V]) Subscribe(buf int) *Subscription[K, V] {
+	s := &Subscription[K, V]{ch: make(chan Change[K, V], buf)}
+	s.C = s.ch
+	s.cancel = l.Observe(func(c Change[K, V]) {
+		select {
+		case s.ch <- c:
+		default:
+			s.dropped.Add(1)
+		}
+	})
+	return s
+}
+
+// Dropped 因通道已满丢弃的通知个数，可以在读取的goroutine中调用
+func (s *Subscription[K, V]) Dropped() int64 {
+	return s.dropped.Load()
+}
+
+// Close 取消订阅并关闭通道，需要与跳表的修改操作在同一个goroutine中调用
+func (s *Subscription[K, V]) Close() {
+	if s.cancel == nil {
+		return
+	}
+	s.cancel()
+	s.cancel = nil
+	close(s.ch)
+}
+
+// notify 通知所有观察者
+func (l *SkipList[K, V]) notify(c Change[K, V]) {
+	for _, o := range l.observers {
+		o.fn(c)
+	}
+}
+
+// notifyInsert 第rank个（从0开始）位置插入了结点
+func (l *SkipList[K, V]) notifyInsert(node *Node[K, V], rank int) {
+	if len(l.observers) == 0 {
+		return
+	}
+	l.notify(Change[K, V]{Kind: ChangeInsert, Key: node.key, NewValue: node.value, OldRank: -1, NewRank: rank})
+}
+
+// notifyUpdate 第rank个结点的value被覆盖
+func (l *SkipList[K, V]) notifyUpdate(node *Node[K, V], rank int, old V) {
+	if len(l.observers) == 0 {
+		return
+	}
+	l.notify(Change[K, V]{Kind: ChangeUpdate, Key: node.key, OldValue: old, NewValue: node.value, OldRank: rank, NewRank: rank})
+}
+
+// notifyDelete 删除了排名为rank的结点
+func (l *SkipList[K, V]) notifyDelete(node *Node[K, V], rank int) {
+	if len(l.observers) == 0 {
+		return
+	}
+	l.notify(Change[K, V]{Kind: ChangeDelete, Key: node.key, OldValue: node.value, OldRank: rank, NewRank: -1})
+}
+
+// notifyReset 跳表被批量重建
+func (l *SkipList[K, V]) notifyReset() {
+	if len(l.observers) == 0 {
+		return
+	}
+	l.notify(Change[K, V]{Kind: ChangeReset, OldRank: -1, NewRank: -1})
+}
@@ -0,0 +1,77 @@
+package algorithm
+
+import "testing"
+
+func TestSkipListObserve(t *testing.T) {
+	l := NewOrdered[int, string]()
+	var changes []Change[int, string]
+	cancel := l.Observe(func(c Change[int, string]) {
+		changes = append(changes, c)
+	})
+
+	l.Insert(10, "a")
+	l.Insert(5, "b")
+	l.Insert(10, "c")
+	l.Delete(5)
+	l.Delete(7)
+	want := []Change[int, string]{
+		{Kind: ChangeInsert, Key: 10, NewValue: "a", OldRank: -1, NewRank: 0},
+		{Kind: ChangeInsert, Key: 5, NewValue: "b", OldRank: -1, NewRank: 0},
+		{Kind: ChangeUpdate, Key: 10, OldValue: "a", NewValue: "c", OldRank: 1, NewRank: 1},
+		{Kind: ChangeDelete, Key: 5, OldValue: "b", OldRank: 0, NewRank: -1},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("changes = %+v", changes)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Fatalf("change %d = %+v, want %+v", i, changes[i], want[i])
+		}
+	}
+
+	for i := 20; i < 25; i++ {
+		l.Insert(i, "x")
+	}
+	changes = nil
+	l.DeleteRangeByRank(1, 3)
+	if len(changes) != 3 || changes[0].Key != 20 || changes[2].OldRank != 3 {
+		t.Fatalf("range delete changes = %+v", changes)
+	}
+
+	changes = nil
+	l.ClearAll()
+	if len(changes) != 1 || changes[0].Kind != ChangeReset {
+		t.Fatalf("clear changes = %+v", changes)
+	}
+
+	cancel()
+	changes = nil
+	l.Insert(1, "z")
+	if len(changes) != 0 {
+		t.Fatal("observer called after cancel")
+	}
+}
+
+func TestSkipListSubscribe(t *testing.T) {
+	l := NewOrdered[int, int]()
+	sub := l.Subscribe(2)
+	for i := 0; i < 5; i++ {
+		l.Insert(i, i)
+	}
+	if sub.Dropped() != 3 {
+		t.Fatalf("dropped = %d, want 3", sub.Dropped())
+	}
+	sub.Close()
+	sub.Close()
+	var keys []int
+	for c := range sub.C {
+		keys = append(keys, c.Key)
+	}
+	if !equalInts(keys, []int{0, 1}) {
+		t.Fatalf("received = %v", keys)
+	}
+	l.Insert(10, 10)
+	if len(l.observers) != 0 {
+		t.Fatal("subscription not removed")
+	}
+}
@@ -0,0 +1,295 @@
+package algorithm
+
+import (
+	"iter"
+	"math"
+	"runtime"
+	"sync/atomic"
+)
+
+// pnode 持久化treap结点，创建后不再修改，修改时复制路径上的结点
+// 中序遍历的顺序与跳表最底层的顺序一致，size 用于按位置修改与按排名查找
+type pnode[K, V any] struct {
+	key         K
+	value       V
+	prio        uint32
+	size        int
+	left, right *pnode[K, V]
+}
+
+func psize[K, V any](t *pnode[K, V]) int {
+	if t == nil {
+		return 0
+	}
+	return t.size
+}
+
+// with 复制结点并替换左右子树
+func (t *pnode[K, V]) with(left, right *pnode[K, V]) *pnode[K, V] {
+	c := *t
+	c.left, c.right = left, right
+	c.size = psize(left) + psize(right) + 1
+	return &c
+}
+
+// psplit 把t拆分为前n个结点与其余结点
+func psplit[K, V any](t *pnode[K, V], n int) (*pnode[K, V], *pnode[K, V]) {
+	if t == nil {
+		return nil, nil
+	}
+	if ls := psize(t.left); n <= ls {
+		a, b := psplit(t.left, n)
+		return a, t.with(b, t.right)
+	}
+	a, b := psplit(t.right, n-psize(t.left)-1)
+	return t.with(t.left, a), b
+}
+
+// pmerge 合并两棵树，a 的结点都排在 b 之前
+func pmerge[K, V any](a, b *pnode[K, V]) *pnode[K, V] {
+	switch {
+	case a == nil:
+		return b
+	case b == nil:
+		return a
+	case a.prio > b.prio:
+		return a.with(a.left, pmerge(a.right, b))
+	default:
+		return b.with(pmerge(a, b.left), b.right)
+	}
+}
+
+// pset 返回把第pos个结点（从1开始）的value替换后的树
+func pset[K, V any](t *pnode[K, V], pos int, value V) *pnode[K, V] {
+	ls := psize(t.left)
+	switch {
+	case pos <= ls:
+		return t.with(pset(t.left, pos, value), t.right)
+	case pos > ls+1:
+		return t.with(t.left, pset(t.right, pos-ls-1, value))
+	}
+	c := *t
+	c.value = value
+	return &c
+}
+
+// pbuild 用有序的结点构建平衡的树，越靠近根的结点优先级越高
+func pbuild[K, V any](nodes []*Node[K, V], depth uint32) *pnode[K, V] {
+	if len(nodes) == 0 {
+		return nil
+	}
+	mid := len(nodes) / 2
+	return &pnode[K, V]{
+		key:   nodes[mid].key,
+		value: nodes[mid].value,
+		prio:  math.MaxUint32 - depth,
+		size:  len(nodes),
+		left:  pbuild(nodes[:mid], depth+1),
+		right: pbuild(nodes[mid+1:], depth+1),
+	}
+}
+
+// shadowState 快照影子树的状态
+type shadowState uint8
+
+const (
+	shadowOff   shadowState = iota // 没有存活的快照，不维护影子树
+	shadowValid                    // 影子树与跳表一致，修改时同步更新
+	shadowStale                    // 经过批量修改，下次 Snapshot 时重建
+)
+
+// Snapshot 跳表的只读快照
+// 快照不可修改，之后对跳表的修改不会影响快照，可以在多个goroutine中并发读取
+// 快照之间以及快照与跳表的影子树共用未修改的结点，不再引用的快照由GC回收
+type Snapshot[K, V any] struct {
+	root *pnode[K, V]
+	cmp  func(a, b K) int
+	ref  *snapshotRef
+}
+
+// snapshotRef 记录快照是否存活，与快照分开分配，便于快照被回收时清理
+type snapshotRef struct {
+	live     *atomic.Int64
+	released atomic.Bool
+}
+
+func (r *snapshotRef) release() {
+	if r.released.CompareAndSwap(false, true) {
+		r.live.Add(-1)
+	}
+}
+
+// Snapshot 返回跳表当前内容的快照，需要与其他修改操作在同一个goroutine中调用
+// 第一次调用时以O(n)构建影子树，之后每次 Insert/Delete 以O(log n)的代价复制修改路径，
+// 获取快照为O(1)；范围删除、拆分合并等批量操作之后，下一次调用会重新构建
+// 所有快照都被 Release 或被GC回收后，跳表停止维护影子树，下一次调用重新构建
+func (l *SkipList[K, V]) Snapshot() *Snapshot[K, V] {
+	if l.shadowState != shadowValid {
+		nodes := make([]*Node[K, V], 0, l.len)
+		for node := l.head.levels[0].next; node != nil; node = node.levels[0].next {
+			nodes = append(nodes, node)
+		}
+		l.shadow = pbuild(nodes, 0)
+		l.shadowState = shadowValid
+	}
+	if l.snapshots == nil {
+		l.snapshots = new(atomic.Int64)
+	}
+	l.snapshots.Add(1)
+	s := &Snapshot[K, V]{root: l.shadow, cmp: l.cmp, ref: &snapshotRef{live: l.snapshots}}
+	runtime.AddCleanup(s, (*snapshotRef).release, s.ref)
+	return s
+}
+
+// Release 释放快照，之后跳表不再为它维护影子树；释放后快照仍然可以读取
+// 不调用时快照被GC回收后自动释放，但影子树占用的内存要等到回收之后才会释放
+func (s *Snapshot[K, V]) Release() {
+	s.ref.release()
+}
+
+// shadowing 是否需要同步更新影子树，没有存活的快照时丢弃影子树
+func (l *SkipList[K, V]) shadowing() bool {
+	if l.shadowState == shadowOff {
+		return false
+	}
+	if l.snapshots.Load() == 0 {
+		l.shadow = nil
+		l.shadowState = shadowOff
+		return false
+	}
+	return l.shadowState == shadowValid
+}
+
+// shadowInsert 在影子树的第pos个位置插入结点
+func (l *SkipList[K, V]) shadowInsert(pos int, node *Node[K, V]) {
+	if !l.shadowing() {
+		return
+	}
+	a, b := psplit(l.shadow, pos-1)
+	t := &pnode[K, V]{key: node.key, value: node.value, prio: l.uint32(), size: 1}
+	l.shadow = pmerge(pmerge(a, t), b)
+}
+
+// shadowRemove 删除影子树从第pos个位置开始的count个结点
+func (l *SkipList[K, V]) shadowRemove(pos, count int) {
+	if !l.shadowing() {
+		return
+	}
+	a, rest := psplit(l.shadow, pos-1)
+	_, b := psplit(rest, count)
+	l.shadow = pmerge(a, b)
+}
+
+// shadowSet 修改影子树第pos个结点的value
+func (l *SkipList[K, V]) shadowSet(pos int, value V) {
+	if l.shadowing() {
+		l.shadow = pset(l.shadow, pos, value)
+	}
+}
+
+// staleShadow 批量修改后标记影子树失效
+func (l *SkipList[K, V]) staleShadow() {
+	if l.shadowState == shadowValid {
+		l.shadow = nil
+		l.shadowState = shadowStale
+	}
+}
+
+// Len 结点个数
+func (s *Snapshot[K, V]) Len() int {
+	return psize(s.root)
+}
+
+// Get 查找key对应的value，multimap模式下返回相等的key中最早插入的一个
+func (s *Snapshot[K, V]) Get(key K) (value V, ok bool) {
+	if t, _ := s.lowerBound(key); t != nil && s.cmp(t.key, key) == 0 {
+		return t.value, true
+	}
+	return
+}
+
+// Rank 返回key的升序排名，从0开始
+func (s *Snapshot[K, V]) Rank(key K) (int, bool) {
+	t, rank := s.lowerBound(key)
+	if t == nil || s.cmp(t.key, key) != 0 {
+		return 0, false
+	}
+	return rank, true
+}
+
+// GetByRank 返回升序排名为rank的结点，rank 从0开始
+func (s *Snapshot[K, V]) GetByRank(rank int) (key K, value V, ok bool) {
+	if rank < 0 || rank >= s.Len() {
+		return
+	}
+	t := s.root
+	for {
+		ls := psize(t.left)
+		switch {
+		case rank < ls:
+			t = t.left
+		case rank > ls:
+			rank -= ls + 1
+			t = t.right
+		default:
+			return t.key, t.value, true
+		}
+	}
+}
+
+// Min 返回最小的结点
+func (s *Snapshot[K, V]) Min() (K, V, bool) {
+	return s.GetByRank(0)
+}
+
+// Max 返回最大的结点
+func (s *Snapshot[K, V]) Max() (K, V, bool) {
+	return s.GetByRank(s.Len() - 1)
+}
+
+// All 按key升序遍历所有结点
+func (s *Snapshot[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		s.walk(s.root, nil, yield)
+	}
+}
+
+// Range 按key升序遍历 [lo, hi) 内的结点
+func (s *Snapshot[K, V]) Range(lo, hi K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		s.walk(s.root, &lo, func(k K, v V) bool {
+			return s.cmp(k, hi) < 0 && yield(k, v)
+		})
+	}
+}
+
+// walk 中序遍历，from 不为nil时只遍历 key 不小于 *from 的结点，返回false表示停止
+func (s *Snapshot[K, V]) walk(t *pnode[K, V], from *K, yield func(K, V) bool) bool {
+	if t == nil {
+		return true
+	}
+	inRange := from == nil || s.cmp(t.key, *from) >= 0
+	if inRange && !s.walk(t.left, from, yield) {
+		return false
+	}
+	if inRange && !yield(t.key, t.value) {
+		return false
+	}
+	return s.walk(t.right, from, yield)
+}
+
+// lowerBound 返回第一个大于等于key的结点及其排名，不存在时结点为nil，排名为结点个数
+func (s *Snapshot[K, V]) lowerBound(key K) (*pnode[K, V], int) {
+	var found *pnode[K, V]
+	rank, foundRank := 0, s.Len()
+	for t := s.root; t != nil; {
+		if s.cmp(t.key, key) >= 0 {
+			found, foundRank = t, rank+psize(t.left)
+			t = t.left
+		} else {
+			rank += psize(t.left) + 1
+			t = t.right
+		}
+	}
+	return found, foundRank
+}
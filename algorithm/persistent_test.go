@@ -0,0 +1,213 @@
+package algorithm
+
+import (
+	"math/rand"
+	"runtime"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// snapshotKeys 返回快照中按顺序排列的key与value
+func snapshotKeys(s *Snapshot[int, int]) (keys, values []int) {
+	for k, v := range s.All() {
+		keys = append(keys, k)
+		values = append(values, v)
+	}
+	return
+}
+
+// listKeys 返回跳表中按顺序排列的key与value
+func listKeys(l *SkipList[int, int]) (keys, values []int) {
+	for k, v := range l.All() {
+		keys = append(keys, k)
+		values = append(values, v)
+	}
+	return
+}
+
+func TestSkipListPersistentSnapshot(t *testing.T) {
+	l := NewOrdered[int, int]()
+	for i := 0; i < 100; i++ {
+		l.Insert(i, i)
+	}
+	s1 := l.Snapshot()
+	keys1, values1 := listKeys(l)
+
+	for i := 0; i < 300; i++ {
+		k := rand.Intn(200)
+		if rand.Intn(3) == 0 {
+			l.Delete(k)
+		} else {
+			l.Insert(k, -k)
+		}
+	}
+	s2 := l.Snapshot()
+	keys2, values2 := listKeys(l)
+	l.Insert(1000, 1000)
+	l.DeleteRangeByRank(0, 9)
+
+	if k, v := snapshotKeys(s1); !equalInts(k, keys1) || !equalInts(v, values1) {
+		t.Fatal("first snapshot changed after writes")
+	}
+	if k, v := snapshotKeys(s2); !equalInts(k, keys2) || !equalInts(v, values2) {
+		t.Fatal("second snapshot does not match the list")
+	}
+	keys3, values3 := listKeys(l)
+	if k, v := snapshotKeys(l.Snapshot()); !equalInts(k, keys3) || !equalInts(v, values3) {
+		t.Fatal("snapshot after range delete does not match the list")
+	}
+
+	if s1.Len() != 100 {
+		t.Fatalf("len = %d, want 100", s1.Len())
+	}
+	if v, ok := s1.Get(42); !ok || v != 42 {
+		t.Fatalf("get = %d, %v", v, ok)
+	}
+	if _, ok := s1.Get(150); ok {
+		t.Fatal("get missing key")
+	}
+	if rank, ok := s1.Rank(42); !ok || rank != 42 {
+		t.Fatalf("rank = %d, %v", rank, ok)
+	}
+	if k, _, ok := s1.GetByRank(99); !ok || k != 99 {
+		t.Fatalf("GetByRank = %d, %v", k, ok)
+	}
+	if k, _, _ := s1.Min(); k != 0 {
+		t.Fatalf("min = %d", k)
+	}
+	if k, _, _ := s1.Max(); k != 99 {
+		t.Fatalf("max = %d", k)
+	}
+	var got []int
+	for k := range s1.Range(10, 15) {
+		got = append(got, k)
+	}
+	if !equalInts(got, []int{10, 11, 12, 13, 14}) {
+		t.Fatalf("range = %v", got)
+	}
+}
+
+func TestSkipListPersistentSnapshotBulk(t *testing.T) {
+	l := NewOrdered[int, int](WithDuplicates())
+	l.Snapshot()
+	if err := l.FromSorted([]int{1, 2, 2, 3}, []int{1, 2, 3, 4}); err != nil {
+		t.Fatal(err)
+	}
+	l.Insert(2, 5)
+	s := l.Snapshot()
+	l.DeleteOne(2, func(v int) bool { return v == 3 })
+	if _, v := snapshotKeys(s); !equalInts(v, []int{1, 2, 3, 5, 4}) {
+		t.Fatalf("snapshot values = %v", v)
+	}
+	if _, v := snapshotKeys(l.Snapshot()); !equalInts(v, []int{1, 2, 5, 4}) {
+		t.Fatalf("snapshot values = %v", v)
+	}
+
+	_, right := l.Split(2)
+	if k, _ := snapshotKeys(l.Snapshot()); !equalInts(k, []int{1}) {
+		t.Fatalf("snapshot after split = %v", k)
+	}
+	l.Merge(right)
+	if k, _ := snapshotKeys(l.Snapshot()); !equalInts(k, []int{1, 2, 2, 3}) {
+		t.Fatalf("snapshot after merge = %v", k)
+	}
+}
+
+func TestSkipListPersistentSnapshotReaders(t *testing.T) {
+	l := NewOrdered[int, int]()
+	snaps := make(chan *Snapshot[int, int], 16)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for s := range snaps {
+				// 写入时保持 value == key*2，且key连续
+				n := 0
+				for k, v := range s.All() {
+					if k != n || v != k*2 {
+						t.Errorf("inconsistent snapshot at %d: %d=%d", n, k, v)
+						return
+					}
+					n++
+				}
+				if n != s.Len() {
+					t.Errorf("len = %d, iterated %d", s.Len(), n)
+				}
+			}
+		}()
+	}
+	for i := 0; i < 500; i++ {
+		l.Insert(i, i*2)
+		if i%10 == 0 {
+			snaps <- l.Snapshot()
+		}
+	}
+	close(snaps)
+	wg.Wait()
+}
+
+func TestSkipListPersistentSnapshotRelease(t *testing.T) {
+	l := NewOrdered[int, int]()
+	for i := 0; i < 10; i++ {
+		l.Insert(i, i)
+	}
+	s1, s2 := l.Snapshot(), l.Snapshot()
+	s1.Release()
+	s1.Release()
+	l.Insert(10, 10)
+	if l.shadowState != shadowValid {
+		t.Fatal("shadow dropped while a snapshot is live")
+	}
+	s2.Release()
+	l.Insert(11, 11)
+	if l.shadowState != shadowOff || l.shadow != nil {
+		t.Fatalf("shadow kept after release, state %d", l.shadowState)
+	}
+	// 释放后快照仍然可以读取
+	if s2.Len() != 10 {
+		t.Fatalf("released snapshot len = %d", s2.Len())
+	}
+	if k, _ := snapshotKeys(l.Snapshot()); len(k) != 12 {
+		t.Fatalf("rebuilt snapshot = %v", k)
+	}
+
+	// 未释放的快照被GC回收后同样停止维护
+	l.Snapshot()
+	for i := 0; i < 100 && l.snapshots.Load() != 0; i++ {
+		runtime.GC()
+		time.Sleep(time.Millisecond)
+	}
+	l.Insert(12, 12)
+	if l.shadowState != shadowOff {
+		t.Fatal("shadow kept after snapshot was collected")
+	}
+}
+
+func TestSkipListPersistentSnapshotRandSource(t *testing.T) {
+	shape := func() []uint32 {
+		l := NewOrdered[int, int](WithRandSource(rand.NewSource(3)))
+		s := l.Snapshot()
+		defer s.Release()
+		for i := 0; i < 50; i++ {
+			l.Insert(i, i)
+		}
+		var prios []uint32
+		var walk func(t *pnode[int, int])
+		walk = func(t *pnode[int, int]) {
+			if t != nil {
+				walk(t.left)
+				prios = append(prios, t.prio)
+				walk(t.right)
+			}
+		}
+		walk(l.shadow)
+		return prios
+	}
+	a, b := shape(), shape()
+	if len(a) != 50 || !slices.Equal(a, b) {
+		t.Fatal("shadow priorities not reproducible with WithRandSource")
+	}
+}
@@ -0,0 +1,74 @@
+package algorithm
+
+// Rank 返回key的升序排名，从0开始
+// multimap模式下返回相等的key中最早插入的一个的排名
+func (l *SkipList[K, V]) Rank(key K) (int, bool) {
+	pre, rank := l.lowerPre(key)
+	if next := pre.levels[0].next; next == nil || l.cmp(next.key, key) != 0 {
+		return 0, false
+	}
+	return rank, true
+}
+
+// RevRank 返回key的降序排名，从0开始
+func (l *SkipList[K, V]) RevRank(key K) (int, bool) {
+	rank, ok := l.Rank(key)
+	if !ok {
+		return 0, false
+	}
+	return int(l.len) - 1 - rank, true
+}
+
+// GetByRank 返回升序排名为rank的结点，rank从0开始
+func (l *SkipList[K, V]) GetByRank(rank int) (*Node[K, V], bool) {
+	if rank < 0 || rank >= int(l.len) {
+		return nil, false
+	}
+	node := l.nodeAt(rank + 1)
+	return node, node != nil
+}
+
+// RangeByRank 返回升序排名在 [start, stop] 内的结点
+// 与redis一致，负数表示从末尾开始计数，-1 为最后一个结点
+func (l *SkipList[K, V]) RangeByRank(start, stop int) []*Node[K, V] {
+	n := int(l.len)
+	if start < 0 {
+		start += n
+	}
+	if stop < 0 {
+		stop += n
+	}
+	if start < 0 {
+		start = 0
+	}
+	if stop >= n {
+		stop = n - 1
+	}
+	if start > stop {
+		return nil
+	}
+
+	ret := make([]*Node[K, V], 0, stop-start+1)
+	node := l.nodeAt(start + 1)
+	for i := start; i <= stop && node != nil; i++ {
+		ret = append(ret, node)
+		node = node.levels[0].next
+	}
+	return ret
+}
+
+// nodeAt 根据跨度定位第pos个结点，pos从1开始
+func (l *SkipList[K, V]) nodeAt(pos int) *Node[K, V] {
+	traversed := 0
+	curNode := l.head
+	for i := int(l.layer) - 1; i >= 0; i-- {
+		for curNode.levels[i].next != nil && traversed+curNode.levels[i].span <= pos {
+			traversed += curNode.levels[i].span
+			curNode = curNode.levels[i].next
+		}
+		if traversed == pos {
+			return curNode
+		}
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package algorithm
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// checkSpans 校验每层的跨度、后向指针与结点位置一致
+func checkSpans[K, V any](t *testing.T, l *SkipList[K, V]) {
+	t.Helper()
+	pos := map[*Node[K, V]]int{}
+	var prev *Node[K, V]
+	i := 1
+	for node := l.head.levels[0].next; node != nil; node = node.levels[0].next {
+		if node.backward != prev {
+			t.Fatalf("backward of node %d is wrong", i)
+		}
+		pos[node] = i
+		prev = node
+		i++
+	}
+	if i-1 != int(l.len) {
+		t.Fatalf("bottom layer has %d nodes, len = %d", i-1, l.len)
+	}
+	if l.tail != prev {
+		t.Fatal("tail is not the last node")
+	}
+	for level := 0; level < int(l.layer); level++ {
+		rank := 0
+		for node := l.head; node != nil; node = node.levels[level].next {
+			next := node.levels[level].next
+			if next == nil {
+				if node.levels[level].span != int(l.len)-rank {
+					t.Fatalf("tail span = %d, want %d", node.levels[level].span, int(l.len)-rank)
+				}
+				break
+			}
+			if node.levels[level].span != pos[next]-rank {
+				t.Fatalf("span = %d, want %d", node.levels[level].span, pos[next]-rank)
+			}
+			rank = pos[next]
+		}
+	}
+}
+
+func TestSkipListRank(t *testing.T) {
+	l := NewOrdered[int, int]()
+	keys := rand.Perm(500)
+	for _, k := range keys {
+		l.Insert(k*2, k)
+		checkSpans(t, l)
+	}
+	for _, k := range keys[:250] {
+		l.Delete(k * 2)
+		checkSpans(t, l)
+	}
+
+	left := append([]int(nil), keys[250:]...)
+	sort.Ints(left)
+	for i, k := range left {
+		if rank, ok := l.Rank(k * 2); !ok || rank != i {
+			t.Fatalf("rank(%d) = %d, %v, want %d", k*2, rank, ok, i)
+		}
+		if rank, ok := l.RevRank(k * 2); !ok || rank != len(left)-1-i {
+			t.Fatalf("revrank(%d) = %d, want %d", k*2, rank, len(left)-1-i)
+		}
+		if node, ok := l.GetByRank(i); !ok || node.GetKey() != k*2 {
+			t.Fatalf("getbyrank(%d) = %v, want %d", i, node.GetKey(), k*2)
+		}
+	}
+	if _, ok := l.Rank(1); ok {
+		t.Fatal("rank of absent key")
+	}
+	if _, ok := l.GetByRank(len(left)); ok {
+		t.Fatal("getbyrank out of range")
+	}
+}
+
+func TestSkipListRangeByRank(t *testing.T) {
+	l := NewOrdered[int, int]()
+	for i := 0; i < 10; i++ {
+		l.Insert(i, i)
+	}
+	tests := []struct {
+		start, stop int
+		want        []int
+	}{
+		{0, 2, []int{0, 1, 2}},
+		{-3, -1, []int{7, 8, 9}},
+		{8, 20, []int{8, 9}},
+		{-20, 1, []int{0, 1}},
+		{5, 4, nil},
+		{10, 12, nil},
+	}
+	for _, tt := range tests {
+		nodes := l.RangeByRank(tt.start, tt.stop)
+		if len(nodes) != len(tt.want) {
+			t.Fatalf("range(%d, %d) len = %d, want %v", tt.start, tt.stop, len(nodes), tt.want)
+		}
+		for i, node := range nodes {
+			if node.GetKey() != tt.want[i] {
+				t.Fatalf("range(%d, %d)[%d] = %d, want %v", tt.start, tt.stop, i, node.GetKey(), tt.want)
+			}
+		}
+	}
+}
@@ -1,324 +1,577 @@
 package algorithm
 
 import (
-	"fmt"
+	"cmp"
 	"math/rand"
+	"os"
+	"sync/atomic"
 )
 
+// skipLevel 结点在某一层的前向指针
+type skipLevel[K, V any] struct {
+	next *Node[K, V]
+	span int // 到同层下一个结点跨越的底层结点数
+}
+
 // Node 跳表节点
-type Node struct {
-	pre    *Node
-	next   *Node
-	down   *Node
-	isHead bool
-	key    Key
-	value  interface{}
+// 每个元素只有一个结点，levels 为各层的前向指针，backward 只在最底层使用
+type Node[K, V any] struct {
+	key      K
+	value    V
+	backward *Node[K, V]
+	levels   []skipLevel[K, V]
 }
 
-func (n *Node) GetValue() (value interface{}) {
+// GetKey 获得结点的key
+func (n *Node[K, V]) GetKey() (key K) {
 	if n == nil {
 		return
 	}
-	value = n.value
+	key = n.key
 	return
 }
 
-func (n *Node) Next() (next *Node, exist bool) {
-	if n == nil || n.next == nil {
+// GetValue 获得结点的value
+func (n *Node[K, V]) GetValue() (value V) {
+	if n == nil {
 		return
 	}
-	next, exist = n.pre, true
+	value = n.value
 	return
 }
 
-func (n *Node) Prev() (pre *Node, exist bool) {
-	if n == nil || n.pre == nil || n.pre.isHead {
+// Next 返回下一个结点
+func (n *Node[K, V]) Next() (next *Node[K, V], exist bool) {
+	if n == nil || n.levels[0].next == nil {
 		return
 	}
-	pre, exist = n.pre, true
+	next, exist = n.levels[0].next, true
 	return
 }
 
-func (n *Node) Down() (down *Node, exist bool) {
-	if n == nil || n.down == nil {
+// Prev 返回上一个结点
+func (n *Node[K, V]) Prev() (pre *Node[K, V], exist bool) {
+	if n == nil || n.backward == nil {
 		return
 	}
-	down, exist = n.down, true
+	pre, exist = n.backward, true
 	return
 }
 
-// Key 索引
-type Key interface {
-	Equal(then Key) bool
-	Less(then Key) bool
-	Check(then Key) bool
+// Down 返回下层对应的结点
+//
+// Deprecated: 结点不再按层拆分，各层共用同一个结点，Down 总是返回false
+func (n *Node[K, V]) Down() (down *Node[K, V], exist bool) {
+	return
+}
+
+// 按层数分配结点，层数较少时结点与前向指针一次分配
+type node1[K, V any] struct {
+	Node[K, V]
+	arr [1]skipLevel[K, V]
+}
+
+type node2[K, V any] struct {
+	Node[K, V]
+	arr [2]skipLevel[K, V]
+}
+
+type node3[K, V any] struct {
+	Node[K, V]
+	arr [3]skipLevel[K, V]
+}
+
+type node4[K, V any] struct {
+	Node[K, V]
+	arr [4]skipLevel[K, V]
+}
+
+// newNode 创建层数为height的结点
+func newNode[K, V any](key K, value V, height int) *Node[K, V] {
+	var n *Node[K, V]
+	switch height {
+	case 1:
+		x := &node1[K, V]{}
+		x.levels = x.arr[:]
+		n = &x.Node
+	case 2:
+		x := &node2[K, V]{}
+		x.levels = x.arr[:]
+		n = &x.Node
+	case 3:
+		x := &node3[K, V]{}
+		x.levels = x.arr[:]
+		n = &x.Node
+	case 4:
+		x := &node4[K, V]{}
+		x.levels = x.arr[:]
+		n = &x.Node
+	default:
+		n = &Node[K, V]{levels: make([]skipLevel[K, V], height)}
+	}
+	n.key, n.value = key, value
+	return n
 }
 
 // SkipList 跳表
-type SkipList struct {
-	top   *Node
+// cmp 比较两个key：a < b 返回负数，a == b 返回0，a > b 返回正数
+// 必须通过 New 或 NewOrdered 创建，零值不能使用；以 Key 接口为索引、零值可用的旧版跳表见 KeySkipList
+type SkipList[K, V any] struct {
+	head  *Node[K, V]
+	tail  *Node[K, V]
 	layer int32
 	len   int32
+	cmp   func(a, b K) int
+	match func(target, cur K) bool
+	opts  options
+	rnd   *rand.Rand
+
+	keyCodec   Codec[K]
+	valueCodec Codec[V]
+
+	shadow      *pnode[K, V] // Snapshot 使用的影子树
+	shadowState shadowState
+	snapshots   *atomic.Int64 // 存活的快照个数
+	observers   []*observer[K, V]
 }
 
-// Print 打印整个跳表
-func (l *SkipList) Print() {
-	fmt.Println("layer: ", l.layer)
-	fmt.Println("len: ", l.len)
-	layerHead := l.top
-	for layerHead != nil {
-		curNode := layerHead.next
-		for curNode != nil {
-			str := fmt.Sprintf("[%#v:%#v]", curNode.key, curNode.value)
-			if curNode.next != nil {
-				str += " -->"
-			}
-			fmt.Printf(str)
-			curNode = curNode.next
+// options 跳表的可选配置
+type options struct {
+	duplicates  bool
+	probability float64
+	maxLevel    int
+	source      rand.Source
+}
+
+const (
+	// DefaultProbability 默认的晋升概率
+	DefaultProbability = 0.5
+	// DefaultMaxLevel 默认的最大层数
+	DefaultMaxLevel = 32
+	// MaxLevelLimit 最大层数的上限
+	MaxLevelLimit = 64
+)
+
+// Option 创建跳表时的可选配置
+type Option func(*options)
+
+// WithDuplicates 允许重复的key（multimap模式）
+// 相等的key按插入顺序排列，Insert 不再覆盖已有的value
+func WithDuplicates() Option {
+	return func(o *options) {
+		o.duplicates = true
+	}
+}
+
+// WithProbability 设置结点晋升到上一层的概率，p 需要在 (0, 1) 之间
+// 概率越小层数越少、越省内存，查找需要的比较次数越多
+func WithProbability(p float64) Option {
+	return func(o *options) {
+		if p > 0 && p < 1 {
+			o.probability = p
 		}
-		fmt.Println("---layer:---")
-		layerHead = layerHead.down
 	}
 }
 
-// Len 只输出底层的结点个数
-func (l *SkipList) Len() int32 {
-	return l.len
+// WithMaxLevel 设置最大层数，n 需要大于0，超过 MaxLevelLimit 时按 MaxLevelLimit 处理
+func WithMaxLevel(n int) Option {
+	return func(o *options) {
+		if n > MaxLevelLimit {
+			n = MaxLevelLimit
+		}
+		if n > 0 {
+			o.maxLevel = n
+		}
+	}
 }
 
-// Sort 将最底层的链表转化为切片输出
-func (l *SkipList) Sort() []interface{} {
-	var ret []interface{}
-	head := l.top
-	if head == nil {
-		return nil
+// WithRandSource 设置生成层数的随机源，固定种子可以得到相同的跳表结构
+// 默认使用 math/rand 的全局随机源
+func WithRandSource(src rand.Source) Option {
+	return func(o *options) {
+		o.source = src
 	}
+}
 
-	for head.down != nil {
-		head = head.down
+// New 根据比较函数创建跳表
+func New[K, V any](cmp func(a, b K) int, opts ...Option) *SkipList[K, V] {
+	l := &SkipList[K, V]{
+		cmp:  cmp,
+		opts: options{probability: DefaultProbability, maxLevel: DefaultMaxLevel},
 	}
-	node := head.next
-	for node != nil {
+	for _, opt := range opts {
+		opt(&l.opts)
+	}
+	if l.opts.source != nil {
+		l.rnd = rand.New(l.opts.source)
+	}
+	l.head = &Node[K, V]{levels: make([]skipLevel[K, V], l.opts.maxLevel)}
+	return l
+}
+
+// NewOrdered 创建以可排序类型为key的跳表
+func NewOrdered[K cmp.Ordered, V any](opts ...Option) *SkipList[K, V] {
+	return New[K, V](cmp.Compare[K], opts...)
+}
+
+// SetMatch 设置Find使用的匹配函数
+// target 为查找的key，cur 为当前比较的结点key，返回true表示匹配成功
+func (l *SkipList[K, V]) SetMatch(match func(target, cur K) bool) {
+	l.match = match
+}
+
+// Print 打印整个跳表
+func (l *SkipList[K, V]) Print() {
+	l.WriteText(os.Stdout)
+}
+
+// Len 只输出底层的结点个数
+func (l *SkipList[K, V]) Len() int32 {
+	return l.len
+}
+
+// Sort 将最底层的链表转化为切片输出
+func (l *SkipList[K, V]) Sort() []V {
+	var ret []V
+	for node := l.head.levels[0].next; node != nil; node = node.levels[0].next {
 		ret = append(ret, node.value)
-		node = node.next
 	}
 	return ret
 }
 
 // GetMin 获得最小值
-func (l *SkipList) GetMin() (*Node, bool) {
-	if l.top == nil {
-		return nil, false
-	}
-	head := l.top
-	for head.down != nil {
-		head = head.down
-	}
-
-	if head == nil {
-		return nil, false
-	}
-	node := head.next
-	if node == nil {
-		return nil, false
-	}
-	return node, true
+func (l *SkipList[K, V]) GetMin() (*Node[K, V], bool) {
+	node := l.head.levels[0].next
+	return node, node != nil
 }
 
 // GetMax 获得最大值
-func (l *SkipList) GetMax() (*Node, bool) {
-	node := l.top
-	if node == nil {
-		return nil, false
-	}
-	for node.down != nil || node.next != nil {
-		for node.next != nil {
-			node = node.next
-		}
-		if node.down != nil {
-			node = node.down
-		}
+func (l *SkipList[K, V]) GetMax() (*Node[K, V], bool) {
+	return l.tail, l.tail != nil
+}
+
+// Insert 插入节点，key已存在时覆盖value
+// multimap模式下总是插入新结点，排在相等的key之后
+func (l *SkipList[K, V]) Insert(key K, value V) {
+	var path searchPath[K, V]
+	if l.opts.duplicates {
+		rank := l.fillPath(key, true, &path)
+		l.insertAt(&path, rank, key, value)
+		return
 	}
-	if node.isHead {
-		return nil, false
+
+	rank := l.fillPath(key, false, &path)
+	if node := path.pres[0].levels[0].next; node != nil && l.cmp(node.key, key) == 0 {
+		l.setValue(node, rank+1, value)
+		return
 	}
-	return node, true
+	l.insertAt(&path, rank, key, value)
 }
 
-// Insert 插入节点
-func (l *SkipList) Insert(key Key, value interface{}) {
-	preNodes, node, ok := l.find(key, 0, false)
-	if ok {
-		foundNode := node
-		for foundNode != nil {
-			foundNode.value = value
-			foundNode = foundNode.down
-		}
-	} else {
-		isBreak := false
-		var downNode *Node
-		for i := len(preNodes) - 1; i >= 0; i-- {
-			preNode := preNodes[i]
-			nextNode := preNode.next
-			aNode := &Node{key: key, value: value}
-
-			preNode.next = aNode
-			aNode.pre = preNode
-			aNode.next = nextNode
-			aNode.down = downNode
-			if nextNode != nil {
-				nextNode.pre = aNode
-			}
+// setValue 覆盖第pos个结点的value，pos从1开始
+func (l *SkipList[K, V]) setValue(node *Node[K, V], pos int, value V) {
+	old := node.value
+	node.value = value
+	l.shadowSet(pos, value)
+	l.notifyUpdate(node, pos-1, old)
+}
 
-			downNode = aNode
-			if !l.needCreatUpNode() {
-				isBreak = true
-				break
-			}
-		}
+// insertAt 在查找路径之后插入新结点，rank 为最底层前驱的位置
+func (l *SkipList[K, V]) insertAt(path *searchPath[K, V], rank int, key K, value V) *Node[K, V] {
+	node := newNode(key, value, l.randomLevel())
+	l.linkNode(path, rank, node)
+	return node
+}
 
-		//建立顶层节点
-		if !isBreak && l.needCreatUpNode() {
-			aNode := &Node{key: key, value: value}
-			head := &Node{down: l.top, isHead: true}
-			aNode.down = downNode
-			aNode.pre = head
-			head.next = aNode
-			l.top = head
-			l.layer++
+// linkNode 把结点按自身高度链接到查找路径之后
+func (l *SkipList[K, V]) linkNode(path *searchPath[K, V], rank int, node *Node[K, V]) {
+	height := len(node.levels)
+	if layer := int(l.layer); height > layer {
+		// 新增的层以头结点为前驱
+		for i := layer; i < height; i++ {
+			l.head.levels[i] = skipLevel[K, V]{span: int(l.len)}
+			path.pres[i] = l.head
+			path.ranks[i] = 0
 		}
+		l.layer = int32(height)
+	}
 
-		// 更新结点数
-		l.len++
+	for i := 0; i < height; i++ {
+		pre := path.pres[i]
+		// 新结点接管前驱的后半段跨度
+		node.levels[i].next = pre.levels[i].next
+		node.levels[i].span = pre.levels[i].span - (rank - path.ranks[i])
+		pre.levels[i].next = node
+		pre.levels[i].span = rank - path.ranks[i] + 1
 	}
+	// 未建立新结点的上层，跨度加一
+	for i := height; i < int(l.layer); i++ {
+		path.pres[i].levels[i].span++
+	}
+
+	if path.pres[0] != l.head {
+		node.backward = path.pres[0]
+	}
+	if next := node.levels[0].next; next != nil {
+		next.backward = node
+	} else {
+		l.tail = node
+	}
+	l.len++
+	l.shadowInsert(rank+1, node)
+	l.notifyInsert(node, rank)
 }
 
 // Delete 删除结点
-func (l *SkipList) Delete(tarKey Key) {
-	_, node, ok := l.find(tarKey, 0, false)
-	if !ok {
+// multimap模式下删除相等的key中最早插入的一个
+func (l *SkipList[K, V]) Delete(tarKey K) {
+	var path searchPath[K, V]
+	l.fillPath(tarKey, false, &path)
+	node := path.pres[0].levels[0].next
+	if node == nil || l.cmp(node.key, tarKey) != 0 {
 		return
 	}
-	foundNode := node
-	for foundNode != nil {
-		pre := foundNode.pre
-		next := foundNode.next
-		pre.next = next
-		if next != nil {
-			next.pre = pre
+	l.deleteNode(node, &path)
+}
+
+// removeAt 删除第pos个底层结点，pos从1开始
+func (l *SkipList[K, V]) removeAt(pos int) {
+	var path searchPath[K, V]
+	traversed := 0
+	curNode := l.head
+	for i := int(l.layer) - 1; i >= 0; i-- {
+		for curNode.levels[i].next != nil && traversed+curNode.levels[i].span < pos {
+			traversed += curNode.levels[i].span
+			curNode = curNode.levels[i].next
 		}
+		path.pres[i] = curNode
+		path.ranks[i] = traversed
+	}
+	if node := curNode.levels[0].next; node != nil {
+		l.deleteNode(node, &path)
+	}
+}
 
-		foundNode = foundNode.down
+// deleteNode 根据查找路径删除结点
+func (l *SkipList[K, V]) deleteNode(node *Node[K, V], path *searchPath[K, V]) {
+	for i := 0; i < int(l.layer); i++ {
+		pre := path.pres[i]
+		if pre.levels[i].next == node {
+			pre.levels[i].span += node.levels[i].span - 1
+			pre.levels[i].next = node.levels[i].next
+		} else {
+			// 结点所在层以上的前驱，跨度减一
+			pre.levels[i].span--
+		}
+	}
+	if next := node.levels[0].next; next != nil {
+		next.backward = node.backward
+	} else {
+		l.tail = node.backward
 	}
 
 	l.len--
-	// 删除头结点
-	head := l.top
-	for head != nil && head.next == nil {
-		head = head.down
+	l.trimLayer()
+	l.shadowRemove(path.ranks[0]+1, 1)
+	l.notifyDelete(node, path.ranks[0])
+}
+
+// trimLayer 删除没有结点的顶层
+func (l *SkipList[K, V]) trimLayer() {
+	for l.layer > 0 && l.head.levels[l.layer-1].next == nil {
 		l.layer--
 	}
-	l.top = head
 }
 
+// DefaultSearchLimit Find 的 searchLimit 为0时最多调用 match 的次数
+const DefaultSearchLimit = 64
+
 // Find 查找结点
-// 只用于搜索匹配玩家
-func (l *SkipList) Find(key Key, searchLimit int) (*Node, bool) {
-	_, node, ok := l.find(key, searchLimit, true)
-	if ok {
-		return node, true
+// key相等的结点优先返回，multimap模式下返回相等的key中最早插入的一个；
+// 设置了匹配函数且key不存在时，从key所在的位置开始向两侧交替查找，
+// 先右后左、按位置由近到远，返回第一个 match 为true的结点
+// searchLimit 为最多调用 match 的次数（不包含查找key所在位置的比较），
+// 0 表示使用 DefaultSearchLimit，负数表示不限制，此时最坏需要遍历整个跳表
+func (l *SkipList[K, V]) Find(key K, searchLimit int) (*Node[K, V], bool) {
+	if searchLimit == 0 {
+		searchLimit = DefaultSearchLimit
+	}
+	pre, _ := l.lowerPre(key)
+	right := pre.levels[0].next
+	if right != nil && l.cmp(right.key, key) == 0 {
+		return right, true
+	}
+	if l.match == nil {
+		return nil, false
 	}
 
+	left := pre
+	if left == l.head {
+		left = nil
+	}
+	checked := 0
+	for left != nil || right != nil {
+		for _, node := range [2]*Node[K, V]{right, left} {
+			if node == nil {
+				continue
+			}
+			if searchLimit > 0 && checked >= searchLimit {
+				return nil, false
+			}
+			checked++
+			if l.match(key, node.key) {
+				return node, true
+			}
+		}
+		if right != nil {
+			right = right.levels[0].next
+		}
+		if left != nil {
+			left = left.backward
+		}
+	}
 	return nil, false
 }
 
-//FindLeft 返回左侧结点
-func (l *SkipList) FindLeft(key Key) (*Node, bool) {
-	_, node, ok := l.find(key, 0, false)
-	if ok {
-		node = node.pre
-	}
+// FindLeft 返回左侧结点，即最后一个小于key的结点，与 Lower 相同
+// key 不存在时同样返回其前驱
+func (l *SkipList[K, V]) FindLeft(key K) (*Node[K, V], bool) {
+	return l.Lower(key)
+}
+
+// FindRight 返回右侧结点，即第一个大于key的结点，与 Higher 相同
+// key 不存在时同样返回其后继
+func (l *SkipList[K, V]) FindRight(key K) (*Node[K, V], bool) {
+	return l.Higher(key)
+}
 
-	if node != nil && !node.isHead {
+// Floor 返回最后一个小于等于key的结点
+// multimap模式下有相等的key时返回最后插入的一个
+func (l *SkipList[K, V]) Floor(key K) (*Node[K, V], bool) {
+	node, _ := l.upperPre(key)
+	if node != l.head {
 		return node, true
 	}
-
 	return nil, false
 }
 
-//FindRight 返回右侧侧结点
-func (l *SkipList) FindRight(key Key) (*Node, bool) {
-	_, node, _ := l.find(key, 0, false)
-	if node != nil && node.next != nil {
-		return node.next, true
-	}
+// Ceiling 返回第一个大于等于key的结点
+// multimap模式下有相等的key时返回最早插入的一个
+func (l *SkipList[K, V]) Ceiling(key K) (*Node[K, V], bool) {
+	node := l.seek(key)
+	return node, node != nil
+}
 
+// Lower 返回最后一个严格小于key的结点
+func (l *SkipList[K, V]) Lower(key K) (*Node[K, V], bool) {
+	node, _ := l.lowerPre(key)
+	if node != l.head {
+		return node, true
+	}
 	return nil, false
 }
 
+// Higher 返回第一个严格大于key的结点
+func (l *SkipList[K, V]) Higher(key K) (*Node[K, V], bool) {
+	node, _ := l.upperPre(key)
+	next := node.levels[0].next
+	return next, next != nil
+}
+
 // ClearAll 清空节点
-func (l *SkipList) ClearAll() {
-	l.top = nil
+func (l *SkipList[K, V]) ClearAll() {
+	l.clear()
+	l.reset()
+}
+
+// clear 清空结点，不通知观察者
+func (l *SkipList[K, V]) clear() {
+	l.head = &Node[K, V]{levels: make([]skipLevel[K, V], l.opts.maxLevel)}
+	l.tail = nil
 	l.len = 0
 	l.layer = 0
 }
 
-func (l *SkipList) find(tarKey Key, searchLimit int, isSearch bool) ([]*Node, *Node, bool) {
-	var cmpCnt int
-	var preNodeList []*Node
-	preNode, curNode := l.top, l.top
-	for curNode != nil {
-		if curNode.isHead {
-			if curNode.next != nil {
-				preNode = curNode
-				curNode = curNode.next
-			} else {
-				preNode = curNode
-				curNode = curNode.down
-				preNodeList = append(preNodeList, preNode)
-			}
-			continue
-		}
+// reset 批量重建之后使影子树失效并通知观察者
+func (l *SkipList[K, V]) reset() {
+	l.staleShadow()
+	l.notifyReset()
+}
 
-		cmpCnt++
-		if searchLimit > 0 && cmpCnt > searchLimit {
-			return preNodeList, preNode, false
-		}
+// searchPath 查找路径，记录每层的前驱结点及其位置（头结点位置为0），下标为层数
+type searchPath[K, V any] struct {
+	pres  [MaxLevelLimit]*Node[K, V]
+	ranks [MaxLevelLimit]int
+}
 
-		if tarKey.Equal(curNode.key) {
-			return nil, curNode, true
+// fillPath 填充每层最后一个key小于key（inclusive 时为小于等于）的结点，返回最底层前驱的位置
+func (l *SkipList[K, V]) fillPath(key K, inclusive bool, path *searchPath[K, V]) int {
+	rank := 0
+	curNode := l.head
+	for i := int(l.layer) - 1; i >= 0; i-- {
+		for next := curNode.levels[i].next; next != nil; next = curNode.levels[i].next {
+			c := l.cmp(next.key, key)
+			if c > 0 || c == 0 && !inclusive {
+				break
+			}
+			rank += curNode.levels[i].span
+			curNode = next
 		}
+		path.pres[i] = curNode
+		path.ranks[i] = rank
+	}
+	if l.layer == 0 {
+		path.pres[0] = l.head
+	}
+	return rank
+}
 
-		if isSearch && tarKey.Check(curNode.key) {
-			return nil, curNode, true
+// lowerPre 返回最后一个key小于key的结点及其位置，可能是头结点
+// 与fillPath不同，不记录查找路径
+func (l *SkipList[K, V]) lowerPre(key K) (*Node[K, V], int) {
+	rank := 0
+	curNode := l.head
+	for i := int(l.layer) - 1; i >= 0; i-- {
+		for next := curNode.levels[i].next; next != nil && l.cmp(next.key, key) < 0; next = curNode.levels[i].next {
+			rank += curNode.levels[i].span
+			curNode = next
 		}
+	}
+	return curNode, rank
+}
 
-		if tarKey.Less(curNode.key) {
-			curNode = preNode.down
-			preNodeList = append(preNodeList, preNode)
-		} else if curNode.next != nil {
-			preNode = curNode
-			curNode = curNode.next
-		} else {
-			preNode = curNode
-			curNode = curNode.down
-			preNodeList = append(preNodeList, preNode)
+// upperPre 返回最后一个key小于等于key的结点及其位置，可能是头结点
+func (l *SkipList[K, V]) upperPre(key K) (*Node[K, V], int) {
+	rank := 0
+	curNode := l.head
+	for i := int(l.layer) - 1; i >= 0; i-- {
+		for next := curNode.levels[i].next; next != nil && l.cmp(next.key, key) <= 0; next = curNode.levels[i].next {
+			rank += curNode.levels[i].span
+			curNode = next
 		}
 	}
-
-	return preNodeList, preNode, false
+	return curNode, rank
 }
 
-// 是否需要创建上层结点, 随机决定，概率可以设置
-func (l *SkipList) needCreatUpNode() bool {
-	if l.top == nil {
-		return true
+// randomLevel 随机生成新结点的层数，每层以 probability 的概率晋升，不超过 maxLevel
+func (l *SkipList[K, V]) randomLevel() int {
+	level := 1
+	for level < l.opts.maxLevel && l.float64() < l.opts.probability {
+		level++
 	}
+	return level
+}
 
-	base := 3
-	if rand.Intn(2*base) < base {
-		return true
+func (l *SkipList[K, V]) float64() float64 {
+	if l.rnd != nil {
+		return l.rnd.Float64()
 	}
+	return rand.Float64()
+}
 
-	return false
+func (l *SkipList[K, V]) uint32() uint32 {
+	if l.rnd != nil {
+		return l.rnd.Uint32()
+	}
+	return rand.Uint32()
 }
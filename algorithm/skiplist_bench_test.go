@@ -0,0 +1,281 @@
+package algorithm
+
+import (
+	"math/rand"
+	"runtime"
+	"testing"
+)
+
+// benchKey 以int为索引的 Key，用于与旧版本对照
+type benchKey int
+
+func (k benchKey) Equal(then Key) bool { return k == then.(benchKey) }
+func (k benchKey) Less(then Key) bool  { return k < then.(benchKey) }
+func (k benchKey) Check(then Key) bool { return false }
+
+// levelNode 旧版本的结点，从最初的实现复制：每个元素在每一层都有一个独立的结点
+// 只保留插入与查找，作为结点布局的性能对照
+type levelNode struct {
+	pre    *levelNode
+	next   *levelNode
+	down   *levelNode
+	isHead bool
+	key    Key
+	value  interface{}
+}
+
+type levelSkipList struct {
+	top   *levelNode
+	layer int32
+	len   int32
+}
+
+func (l *levelSkipList) Insert(key Key, value interface{}) {
+	preNodes, node, ok := l.find(key, 0, false)
+	if ok {
+		foundNode := node
+		for foundNode != nil {
+			foundNode.value = value
+			foundNode = foundNode.down
+		}
+	} else {
+		isBreak := false
+		var downNode *levelNode
+		for i := len(preNodes) - 1; i >= 0; i-- {
+			preNode := preNodes[i]
+			nextNode := preNode.next
+			aNode := &levelNode{key: key, value: value}
+
+			preNode.next = aNode
+			aNode.pre = preNode
+			aNode.next = nextNode
+			aNode.down = downNode
+			if nextNode != nil {
+				nextNode.pre = aNode
+			}
+
+			downNode = aNode
+			if !l.needCreatUpNode() {
+				isBreak = true
+				break
+			}
+		}
+
+		//建立顶层节点
+		if !isBreak && l.needCreatUpNode() {
+			aNode := &levelNode{key: key, value: value}
+			head := &levelNode{down: l.top, isHead: true}
+			aNode.down = downNode
+			aNode.pre = head
+			head.next = aNode
+			l.top = head
+			l.layer++
+		}
+
+		// 更新结点数
+		l.len++
+	}
+}
+
+func (l *levelSkipList) Find(key Key, searchLimit int) (*levelNode, bool) {
+	_, node, ok := l.find(key, searchLimit, true)
+	if ok {
+		return node, true
+	}
+
+	return nil, false
+}
+
+func (l *levelSkipList) find(tarKey Key, searchLimit int, isSearch bool) ([]*levelNode, *levelNode, bool) {
+	var cmpCnt int
+	var preNodeList []*levelNode
+	preNode, curNode := l.top, l.top
+	for curNode != nil {
+		if curNode.isHead {
+			if curNode.next != nil {
+				preNode = curNode
+				curNode = curNode.next
+			} else {
+				preNode = curNode
+				curNode = curNode.down
+				preNodeList = append(preNodeList, preNode)
+			}
+			continue
+		}
+
+		cmpCnt++
+		if searchLimit > 0 && cmpCnt > searchLimit {
+			return preNodeList, preNode, false
+		}
+
+		if tarKey.Equal(curNode.key) {
+			return nil, curNode, true
+		}
+
+		if isSearch && tarKey.Check(curNode.key) {
+			return nil, curNode, true
+		}
+
+		if tarKey.Less(curNode.key) {
+			curNode = preNode.down
+			preNodeList = append(preNodeList, preNode)
+		} else if curNode.next != nil {
+			preNode = curNode
+			curNode = curNode.next
+		} else {
+			preNode = curNode
+			curNode = curNode.down
+			preNodeList = append(preNodeList, preNode)
+		}
+	}
+
+	return preNodeList, preNode, false
+}
+
+// 是否需要创建上层结点, 随机决定，概率可以设置
+func (l *levelSkipList) needCreatUpNode() bool {
+	if l.top == nil {
+		return true
+	}
+
+	base := 3
+	if rand.Intn(2*base) < base {
+		return true
+	}
+
+	return false
+}
+
+const benchEntries = 100000
+
+// heapBytes 返回build分配并保留的堆内存
+// GC 可能回收了build之前分配的内存使堆反而变小，此时重新采样，多次失败时终止基准测试
+func heapBytes(b *testing.B, build func() any) uint64 {
+	for i := 0; i < 5; i++ {
+		var before, after runtime.MemStats
+		runtime.GC()
+		runtime.ReadMemStats(&before)
+		v := build()
+		runtime.GC()
+		runtime.ReadMemStats(&after)
+		runtime.KeepAlive(v)
+		if after.HeapAlloc > before.HeapAlloc {
+			return after.HeapAlloc - before.HeapAlloc
+		}
+	}
+	b.Fatal("heap shrank in every sample")
+	return 0
+}
+
+func BenchmarkSkipListInsert(b *testing.B) {
+	keys := rand.Perm(benchEntries)
+	b.Run("tower", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			l := NewOrdered[int, int]()
+			for _, k := range keys {
+				l.Insert(k, k)
+			}
+		}
+	})
+	b.Run("tower-key", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			l := NewKeySkipList()
+			for _, k := range keys {
+				l.Insert(benchKey(k), k)
+			}
+		}
+	})
+	b.Run("level", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			l := &levelSkipList{}
+			for _, k := range keys {
+				l.Insert(benchKey(k), k)
+			}
+		}
+	})
+}
+
+// BenchmarkSkipListMemory 比较每个元素占用的内存
+// tower-key 与 level 的key和value类型相同，只有结点布局不同
+func BenchmarkSkipListMemory(b *testing.B) {
+	keys := rand.Perm(benchEntries)
+	b.Run("tower", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			bytes := heapBytes(b, func() any {
+				l := NewOrdered[int, int]()
+				for _, k := range keys {
+					l.Insert(k, k)
+				}
+				return l
+			})
+			b.ReportMetric(float64(bytes)/benchEntries, "B/entry")
+		}
+	})
+	b.Run("tower-key", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			bytes := heapBytes(b, func() any {
+				l := NewKeySkipList()
+				for _, k := range keys {
+					l.Insert(benchKey(k), k)
+				}
+				return l
+			})
+			b.ReportMetric(float64(bytes)/benchEntries, "B/entry")
+		}
+	})
+	b.Run("level", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			bytes := heapBytes(b, func() any {
+				l := &levelSkipList{}
+				for _, k := range keys {
+					l.Insert(benchKey(k), k)
+				}
+				return l
+			})
+			b.ReportMetric(float64(bytes)/benchEntries, "B/entry")
+		}
+	})
+}
+
+func BenchmarkSkipListFind(b *testing.B) {
+	keys := rand.Perm(benchEntries)
+	tower, towerKey, level := NewOrdered[int, int](), NewKeySkipList(), &levelSkipList{}
+	for _, k := range keys {
+		tower.Insert(k, k)
+		towerKey.Insert(benchKey(k), k)
+		level.Insert(benchKey(k), k)
+	}
+	b.Run("tower", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			tower.Find(keys[i%benchEntries], 0)
+		}
+	})
+	b.Run("tower-key", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			towerKey.Find(benchKey(keys[i%benchEntries]), 0)
+		}
+	})
+	b.Run("level", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			level.Find(benchKey(keys[i%benchEntries]), 0)
+		}
+	})
+}
+
+func TestLevelSkipListBaseline(t *testing.T) {
+	l := &levelSkipList{}
+	for _, k := range rand.Perm(1000) {
+		l.Insert(benchKey(k), k)
+	}
+	if l.len != 1000 {
+		t.Fatalf("len = %d, want 1000", l.len)
+	}
+	for k := 0; k < 1000; k++ {
+		if n, ok := l.Find(benchKey(k), 0); !ok || n.value != k {
+			t.Fatalf("find(%d) = %v", k, ok)
+		}
+	}
+}
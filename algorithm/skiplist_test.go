@@ -0,0 +1,259 @@
+package algorithm
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestSkipListInsertDelete(t *testing.T) {
+	l := NewOrdered[int, string]()
+	keys := rand.Perm(200)
+	for _, k := range keys {
+		l.Insert(k, "v")
+	}
+	l.Insert(10, "ten")
+	if l.Len() != 200 {
+		t.Fatalf("len = %d, want 200", l.Len())
+	}
+	if node, ok := l.Find(10, 0); !ok || node.GetValue() != "ten" {
+		t.Fatalf("find 10 = %v, %v", node.GetValue(), ok)
+	}
+
+	for _, k := range keys[:100] {
+		l.Delete(k)
+	}
+	l.Delete(1000)
+	if l.Len() != 100 {
+		t.Fatalf("len = %d, want 100", l.Len())
+	}
+	left := keys[100:]
+	sort.Ints(left)
+	if node, ok := l.GetMin(); !ok || node.GetKey() != left[0] {
+		t.Fatalf("min = %v, want %d", node.GetKey(), left[0])
+	}
+	if node, ok := l.GetMax(); !ok || node.GetKey() != left[len(left)-1] {
+		t.Fatalf("max = %v, want %d", node.GetKey(), left[len(left)-1])
+	}
+	for _, k := range keys[:100] {
+		if _, ok := l.Find(k, 0); ok {
+			t.Fatalf("deleted key %d still found", k)
+		}
+	}
+
+	for _, k := range left {
+		l.Delete(k)
+	}
+	if l.Len() != 0 || l.tail != nil || l.layer != 0 {
+		t.Fatalf("list not empty: len=%d layer=%d", l.Len(), l.layer)
+	}
+	if _, ok := l.GetMin(); ok {
+		t.Fatal("min of empty list")
+	}
+	if _, ok := l.GetMax(); ok {
+		t.Fatal("max of empty list")
+	}
+}
+
+func TestSkipListFindLeftRight(t *testing.T) {
+	l := NewOrdered[int, int]()
+	for i := 0; i < 100; i += 10 {
+		l.Insert(i, i)
+	}
+
+	if node, ok := l.FindLeft(50); !ok || node.GetValue() != 40 {
+		t.Fatalf("left of 50 = %v, %v", node.GetValue(), ok)
+	}
+	if node, ok := l.FindLeft(55); !ok || node.GetValue() != 50 {
+		t.Fatalf("left of 55 = %v, %v", node.GetValue(), ok)
+	}
+	if _, ok := l.FindLeft(0); ok {
+		t.Fatal("left of min")
+	}
+	if node, ok := l.FindRight(50); !ok || node.GetValue() != 60 {
+		t.Fatalf("right of 50 = %v, %v", node.GetValue(), ok)
+	}
+	if node, ok := l.FindRight(-5); !ok || node.GetValue() != 0 {
+		t.Fatalf("right of -5 = %v, %v", node.GetValue(), ok)
+	}
+	if _, ok := l.FindRight(90); ok {
+		t.Fatal("right of max")
+	}
+}
+
+func TestSkipListFloorCeiling(t *testing.T) {
+	l := NewOrdered[int, int]()
+	for i := 10; i <= 50; i += 10 {
+		l.Insert(i, i)
+	}
+	cases := []struct {
+		key                           int
+		floor, ceiling, lower, higher int // 0 表示不存在
+	}{
+		{5, 0, 10, 0, 10},
+		{10, 10, 10, 0, 20},
+		{25, 20, 30, 20, 30},
+		{30, 30, 30, 20, 40},
+		{50, 50, 50, 40, 0},
+		{55, 50, 0, 50, 0},
+	}
+	for _, c := range cases {
+		for _, f := range []struct {
+			name string
+			fn   func(int) (*Node[int, int], bool)
+			want int
+		}{
+			{"floor", l.Floor, c.floor},
+			{"ceiling", l.Ceiling, c.ceiling},
+			{"lower", l.Lower, c.lower},
+			{"higher", l.Higher, c.higher},
+			{"left", l.FindLeft, c.lower},
+			{"right", l.FindRight, c.higher},
+		} {
+			node, ok := f.fn(c.key)
+			if ok != (f.want != 0) || node.GetKey() != f.want {
+				t.Fatalf("%s(%d) = %v, %v, want %d", f.name, c.key, node.GetKey(), ok, f.want)
+			}
+		}
+	}
+
+	dup := NewOrdered[int, string](WithDuplicates())
+	for _, v := range []string{"a", "b", "c"} {
+		dup.Insert(1, v)
+	}
+	dup.Insert(2, "d")
+	if node, _ := dup.Floor(1); node.GetValue() != "c" {
+		t.Fatalf("floor in multimap = %s, want c", node.GetValue())
+	}
+	if node, _ := dup.Ceiling(1); node.GetValue() != "a" {
+		t.Fatalf("ceiling in multimap = %s, want a", node.GetValue())
+	}
+	if node, _ := dup.Higher(1); node.GetValue() != "d" {
+		t.Fatalf("higher in multimap = %s, want d", node.GetValue())
+	}
+}
+
+func TestSkipListSort(t *testing.T) {
+	l := New[string, int](func(a, b string) int {
+		switch {
+		case a < b:
+			return -1
+		case a > b:
+			return 1
+		}
+		return 0
+	})
+	for i, k := range []string{"d", "b", "a", "c"} {
+		l.Insert(k, i)
+	}
+	got := l.Sort()
+	want := []int{2, 1, 3, 0}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("sort = %v, want %v", got, want)
+		}
+	}
+}
+
+// ratingKey 测试用的匹配key，分差在 tolerance 内视为匹配
+type ratingKey struct {
+	id        int
+	rating    int
+	tolerance int
+}
+
+func (k ratingKey) Equal(then Key) bool {
+	return k.id == then.(ratingKey).id
+}
+
+func (k ratingKey) Less(then Key) bool {
+	o := then.(ratingKey)
+	if k.rating != o.rating {
+		return k.rating < o.rating
+	}
+	return k.id < o.id
+}
+
+func (k ratingKey) Check(then Key) bool {
+	o := then.(ratingKey)
+	return k.id != o.id && k.rating-o.rating <= k.tolerance && o.rating-k.rating <= k.tolerance
+}
+
+func TestKeySkipList(t *testing.T) {
+	var l KeySkipList
+	for i := 0; i < 50; i++ {
+		l.Insert(ratingKey{id: i, rating: i * 100}, i)
+	}
+	if l.Len() != 50 {
+		t.Fatalf("len = %d, want 50", l.Len())
+	}
+
+	node, ok := l.Find(ratingKey{id: -1, rating: 2030, tolerance: 50}, 0)
+	if !ok || node.GetValue().(int) != 20 {
+		t.Fatalf("find = %v, %v", node.GetValue(), ok)
+	}
+	if _, ok = l.Find(ratingKey{id: -1, rating: 2050, tolerance: 10}, 0); ok {
+		t.Fatal("found player out of tolerance")
+	}
+
+	l.Delete(ratingKey{id: 20, rating: 2000})
+	if _, ok = l.Find(ratingKey{id: -1, rating: 2030, tolerance: 50}, 0); ok {
+		t.Fatal("found deleted player")
+	}
+	if node, ok := l.GetMin(); !ok || node.GetValue().(int) != 0 {
+		t.Fatalf("min = %v, %v", node.GetValue(), ok)
+	}
+
+	l.ClearAll()
+	if l.Len() != 0 || len(l.Sort()) != 0 {
+		t.Fatal("clear failed")
+	}
+}
+
+// levelCounts 返回每层（自上而下）的结点个数
+func levelCounts[K, V any](l *SkipList[K, V]) []int {
+	var counts []int
+	for i := int(l.layer) - 1; i >= 0; i-- {
+		cnt := 0
+		for node := l.head.levels[i].next; node != nil; node = node.levels[i].next {
+			cnt++
+		}
+		counts = append(counts, cnt)
+	}
+	return counts
+}
+
+func TestSkipListRandSource(t *testing.T) {
+	build := func() *SkipList[int, int] {
+		l := NewOrdered[int, int](WithRandSource(rand.NewSource(42)))
+		for i := 0; i < 1000; i++ {
+			l.Insert(i, i)
+		}
+		return l
+	}
+	a, b := levelCounts(build()), levelCounts(build())
+	if !equalInts(a, b) {
+		t.Fatalf("layouts differ with same seed: %v vs %v", a, b)
+	}
+}
+
+func TestSkipListLevelOptions(t *testing.T) {
+	l := NewOrdered[int, int](WithProbability(0.9), WithMaxLevel(4), WithRandSource(rand.NewSource(1)))
+	for i := 0; i < 1000; i++ {
+		l.Insert(i, i)
+	}
+	checkSpans(t, l)
+	if l.layer != 4 {
+		t.Fatalf("layer = %d, want 4", l.layer)
+	}
+
+	l = NewOrdered[int, int](WithProbability(0.25), WithRandSource(rand.NewSource(1)))
+	for i := 0; i < 1000; i++ {
+		l.Insert(i, i)
+	}
+	counts := levelCounts(l)
+	// 第二层的结点数应接近总数的1/4
+	if second := counts[len(counts)-2]; second < 150 || second > 350 {
+		t.Fatalf("level counts = %v", counts)
+	}
+}
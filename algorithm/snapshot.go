@@ -0,0 +1,258 @@
+package algorithm
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash"
+	"hash/crc32"
+	"io"
+	"slices"
+)
+
+// 快照格式：
+//
+//	magic(4) version(1) count(uvarint)
+//	count * [keyLen(uvarint) key valueLen(uvarint) value]
+//	crc32(4, 大端，覆盖magic之后的所有内容)
+const (
+	snapshotMagic   = "GSKL"
+	snapshotVersion = 1
+	// snapshotMaxField 单个key或value的最大长度
+	snapshotMaxField = 1 << 30
+	// snapshotChunk 按块读取字段，内存随实际读到的数据增长，防止损坏的长度申请过大的内存
+	snapshotChunk = 64 << 10
+)
+
+var (
+	// ErrNoCodec 未设置key或value的编解码器
+	ErrNoCodec = errors.New("skiplist: codec not set")
+	// ErrSnapshotFormat 快照格式错误
+	ErrSnapshotFormat = errors.New("skiplist: invalid snapshot format")
+	// ErrSnapshotChecksum 快照校验和错误
+	ErrSnapshotChecksum = errors.New("skiplist: snapshot checksum mismatch")
+	// ErrSnapshotOrder 快照中的key没有按顺序排列
+	ErrSnapshotOrder = errors.New("skiplist: snapshot keys out of order")
+)
+
+// SetCodec 设置持久化使用的key和value编解码器
+func (l *SkipList[K, V]) SetCodec(keyCodec Codec[K], valueCodec Codec[V]) {
+	l.keyCodec = keyCodec
+	l.valueCodec = valueCodec
+}
+
+// MarshalBinary 将跳表序列化为快照
+func (l *SkipList[K, V]) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := l.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary 从快照恢复跳表，原有的结点会被清空
+func (l *SkipList[K, V]) UnmarshalBinary(data []byte) error {
+	_, err := l.ReadFrom(bytes.NewReader(data))
+	return err
+}
+
+// WriteTo 将快照按key升序写入w
+func (l *SkipList[K, V]) WriteTo(w io.Writer) (int64, error) {
+	if l.keyCodec == nil || l.valueCodec == nil {
+		return 0, ErrNoCodec
+	}
+	cw := &countWriter{w: w}
+	bw := bufio.NewWriter(cw)
+	if _, err := bw.WriteString(snapshotMagic); err != nil {
+		return cw.n, err
+	}
+
+	crc := crc32.NewIEEE()
+	body := io.MultiWriter(bw, crc)
+	var scratch []byte
+	scratch = append(scratch, snapshotVersion)
+	scratch = binary.AppendUvarint(scratch, uint64(l.len))
+	if _, err := body.Write(scratch); err != nil {
+		return cw.n, err
+	}
+	for node := l.head.levels[0].next; node != nil; node = node.levels[0].next {
+		k, err := l.keyCodec.Encode(node.key)
+		if err != nil {
+			return cw.n, err
+		}
+		v, err := l.valueCodec.Encode(node.value)
+		if err != nil {
+			return cw.n, err
+		}
+		scratch = binary.AppendUvarint(scratch[:0], uint64(len(k)))
+		scratch = append(scratch, k...)
+		scratch = binary.AppendUvarint(scratch, uint64(len(v)))
+		scratch = append(scratch, v...)
+		if _, err = body.Write(scratch); err != nil {
+			return cw.n, err
+		}
+	}
+	if _, err := bw.Write(binary.BigEndian.AppendUint32(scratch[:0], crc.Sum32())); err != nil {
+		return cw.n, err
+	}
+	err := bw.Flush()
+	return cw.n, err
+}
+
+// ReadFrom 从r读取快照恢复跳表，原有的结点会被清空
+// 快照中的结点已经有序，按顺序在尾部追加，构建的时间复杂度为O(n)
+// 只读取快照本身的字节，r中之后的数据不受影响；r 没有实现 io.ByteReader 时按字节读取变长整数，
+// 读取文件等时可以先用 bufio 包装
+// 读取失败时跳表保持原样
+func (l *SkipList[K, V]) ReadFrom(r io.Reader) (int64, error) {
+	if l.keyCodec == nil || l.valueCodec == nil {
+		return 0, ErrNoCodec
+	}
+	br, ok := r.(byteReader)
+	if !ok {
+		br = &singleByteReader{Reader: r}
+	}
+	n, err := l.readSnapshot(br)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return n, err
+}
+
+func (l *SkipList[K, V]) readSnapshot(br byteReader) (int64, error) {
+	magic := make([]byte, len(snapshotMagic))
+	if _, err := io.ReadFull(br, magic); err != nil {
+		return 0, err
+	}
+	if string(magic) != snapshotMagic {
+		return 0, ErrSnapshotFormat
+	}
+
+	hr := &hashReader{r: br, h: crc32.NewIEEE(), n: int64(len(magic))}
+	version, err := hr.ReadByte()
+	if err != nil {
+		return hr.n, err
+	}
+	if version != snapshotVersion {
+		return hr.n, ErrSnapshotFormat
+	}
+	count, err := binary.ReadUvarint(hr)
+	if err != nil {
+		return hr.n, err
+	}
+
+	// 在新的跳表上构建，成功后再替换，失败时不影响原来的结点
+	tmp := &SkipList[K, V]{cmp: l.cmp, opts: l.opts, rnd: l.rnd}
+	b := newBuilder(tmp)
+	for i := uint64(0); i < count; i++ {
+		kb, err := readField(hr)
+		if err != nil {
+			return hr.n, err
+		}
+		vb, err := readField(hr)
+		if err != nil {
+			return hr.n, err
+		}
+		key, err := l.keyCodec.Decode(kb)
+		if err != nil {
+			return hr.n, err
+		}
+		value, err := l.valueCodec.Decode(vb)
+		if err != nil {
+			return hr.n, err
+		}
+		if tmp.tail != nil {
+			c := l.cmp(tmp.tail.key, key)
+			if c > 0 || c == 0 && !l.opts.duplicates {
+				return hr.n, ErrSnapshotOrder
+			}
+		}
+		b.append(key, value, tmp.randomLevel())
+	}
+	b.finish()
+
+	sum := hr.h.Sum32()
+	var tail [4]byte
+	if _, err := io.ReadFull(br, tail[:]); err != nil {
+		return hr.n, err
+	}
+	if binary.BigEndian.Uint32(tail[:]) != sum {
+		return hr.n + 4, ErrSnapshotChecksum
+	}
+
+	l.head, l.tail, l.len, l.layer = tmp.head, tmp.tail, tmp.len, tmp.layer
+	l.reset()
+	return hr.n + 4, nil
+}
+
+// readField 读取带长度前缀的字段
+func readField(r *hashReader) ([]byte, error) {
+	size, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+	if size > snapshotMaxField {
+		return nil, ErrSnapshotFormat
+	}
+	buf := make([]byte, 0, min(size, snapshotChunk))
+	for uint64(len(buf)) < size {
+		n := int(min(size-uint64(len(buf)), snapshotChunk))
+		buf = slices.Grow(buf, n)
+		if _, err = io.ReadFull(r, buf[len(buf):len(buf)+n]); err != nil {
+			return nil, err
+		}
+		buf = buf[:len(buf)+n]
+	}
+	return buf, nil
+}
+
+type byteReader interface {
+	io.Reader
+	io.ByteReader
+}
+
+// singleByteReader 不预读的 io.ByteReader
+type singleByteReader struct {
+	io.Reader
+	b [1]byte
+}
+
+func (r *singleByteReader) ReadByte() (byte, error) {
+	_, err := io.ReadFull(r.Reader, r.b[:])
+	return r.b[0], err
+}
+
+// hashReader 读取的同时计算校验和并统计字节数
+type hashReader struct {
+	r byteReader
+	h hash.Hash32
+	n int64
+}
+
+func (r *hashReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.h.Write(p[:n])
+	r.n += int64(n)
+	return n, err
+}
+
+func (r *hashReader) ReadByte() (byte, error) {
+	c, err := r.r.ReadByte()
+	if err == nil {
+		r.h.Write([]byte{c})
+		r.n++
+	}
+	return c, err
+}
+
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (w *countWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.n += int64(n)
+	return n, err
+}
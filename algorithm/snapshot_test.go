@@ -0,0 +1,168 @@
+package algorithm
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math/rand"
+	"runtime"
+	"testing"
+)
+
+type snapshotPlayer struct {
+	Name  string
+	Level int
+}
+
+func TestSkipListSnapshot(t *testing.T) {
+	l := NewOrdered[int64, snapshotPlayer]()
+	l.SetCodec(IntCodec[int64]{}, JSONCodec[snapshotPlayer]{})
+	for _, k := range rand.Perm(1000) {
+		l.Insert(int64(k-500), snapshotPlayer{Name: "p", Level: k})
+	}
+
+	data, err := l.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := NewOrdered[int64, snapshotPlayer]()
+	r.SetCodec(IntCodec[int64]{}, JSONCodec[snapshotPlayer]{})
+	r.Insert(10000, snapshotPlayer{})
+	if err = r.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	checkSpans(t, r)
+	if r.Len() != 1000 {
+		t.Fatalf("len = %d, want 1000", r.Len())
+	}
+	i := int64(-500)
+	for k, v := range r.All() {
+		if k != i || v.Level != int(i+500) {
+			t.Fatalf("entry %d = %d:%+v", i, k, v)
+		}
+		i++
+	}
+	if rank, _ := r.Rank(0); rank != 500 {
+		t.Fatalf("rank(0) = %d, want 500", rank)
+	}
+	r.Insert(-1000, snapshotPlayer{})
+	r.Delete(0)
+	checkSpans(t, r)
+}
+
+func TestSkipListSnapshotStream(t *testing.T) {
+	l := NewOrdered[string, float64](WithDuplicates())
+	l.SetCodec(StringCodec{}, Float64Codec{})
+	l.Insert("b", 2)
+	l.Insert("a", 1)
+	l.Insert("b", 2.5)
+
+	var buf bytes.Buffer
+	n, err := l.WriteTo(&buf)
+	if err != nil || n != int64(buf.Len()) {
+		t.Fatalf("writeto = %d, %v, buffered %d", n, err, buf.Len())
+	}
+	r := NewOrdered[string, float64](WithDuplicates())
+	r.SetCodec(StringCodec{}, Float64Codec{})
+	if n, err = r.ReadFrom(&buf); err != nil || n != int64(len(l.mustMarshal(t))) {
+		t.Fatalf("readfrom = %d, %v", n, err)
+	}
+	if got := r.FindAll("b"); len(got) != 2 || got[0] != 2 || got[1] != 2.5 {
+		t.Fatalf("findall = %v", got)
+	}
+
+	empty := NewOrdered[string, float64]()
+	empty.SetCodec(StringCodec{}, Float64Codec{})
+	data, _ := empty.MarshalBinary()
+	if err = r.UnmarshalBinary(data); err != nil || r.Len() != 0 {
+		t.Fatalf("unmarshal empty = %v, len %d", err, r.Len())
+	}
+}
+
+func TestSkipListSnapshotConcatenated(t *testing.T) {
+	a := NewOrdered[int, int]()
+	a.SetCodec(IntCodec[int]{}, IntCodec[int]{})
+	a.Insert(1, 10)
+	b := NewOrdered[int, int]()
+	b.SetCodec(IntCodec[int]{}, IntCodec[int]{})
+	b.Insert(2, 20)
+	b.Insert(3, 30)
+
+	var buf bytes.Buffer
+	a.WriteTo(&buf)
+	b.WriteTo(&buf)
+	size := int64(buf.Len())
+	// 不实现 io.ByteReader 的reader 也不能多读
+	for _, r := range []io.Reader{bytes.NewReader(buf.Bytes()), struct{ io.Reader }{bytes.NewReader(buf.Bytes())}} {
+		got := NewOrdered[int, int]()
+		got.SetCodec(IntCodec[int]{}, IntCodec[int]{})
+		n1, err := got.ReadFrom(r)
+		if err != nil || got.Len() != 1 {
+			t.Fatalf("first = %d, %v", n1, err)
+		}
+		n2, err := got.ReadFrom(r)
+		if err != nil || got.Len() != 2 || n1+n2 != size {
+			t.Fatalf("second = %d, %v, len %d", n2, err, got.Len())
+		}
+	}
+}
+
+func TestSkipListSnapshotErrors(t *testing.T) {
+	l := NewOrdered[int, int]()
+	if _, err := l.MarshalBinary(); err != ErrNoCodec {
+		t.Fatalf("err = %v, want ErrNoCodec", err)
+	}
+	l.SetCodec(IntCodec[int]{}, IntCodec[int]{})
+	for i := 0; i < 10; i++ {
+		l.Insert(i, i)
+	}
+	data := l.mustMarshal(t)
+
+	r := NewOrdered[int, int]()
+	r.SetCodec(IntCodec[int]{}, IntCodec[int]{})
+	r.Insert(1, 1)
+
+	bad := append([]byte(nil), data...)
+	// 修改最后一个value，编码仍然合法
+	bad[len(bad)-5] += 2
+	if err := r.UnmarshalBinary(bad); err != ErrSnapshotChecksum {
+		t.Fatalf("err = %v, want ErrSnapshotChecksum", err)
+	}
+	if err := r.UnmarshalBinary(data[:len(data)-3]); err == nil {
+		t.Fatal("truncated snapshot accepted")
+	}
+	if err := r.UnmarshalBinary([]byte("nope")); err != ErrSnapshotFormat {
+		t.Fatalf("err = %v, want ErrSnapshotFormat", err)
+	}
+	// 损坏的字段长度不会按声明的长度申请内存
+	huge := append([]byte(snapshotMagic), snapshotVersion, 1)
+	huge = binary.AppendUvarint(huge, snapshotMaxField)
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	if err := r.UnmarshalBinary(huge); err != io.ErrUnexpectedEOF {
+		t.Fatalf("err = %v, want ErrUnexpectedEOF", err)
+	}
+	runtime.ReadMemStats(&after)
+	if alloc := after.TotalAlloc - before.TotalAlloc; alloc > 1<<20 {
+		t.Fatalf("allocated %d bytes for corrupt field", alloc)
+	}
+	// 失败时保持原样
+	if r.Len() != 1 {
+		t.Fatalf("len = %d after failed restore", r.Len())
+	}
+
+	desc := New[int, int](func(a, b int) int { return b - a })
+	desc.SetCodec(IntCodec[int]{}, IntCodec[int]{})
+	if err := desc.UnmarshalBinary(data); err != ErrSnapshotOrder {
+		t.Fatalf("err = %v, want ErrSnapshotOrder", err)
+	}
+}
+
+func (l *SkipList[K, V]) mustMarshal(t *testing.T) []byte {
+	t.Helper()
+	data, err := l.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
@@ -0,0 +1,152 @@
+package algorithm
+
+import "math/rand"
+
+// Split 按key把跳表拆成两个：left 为原跳表，保留小于key的结点；right 为新跳表，包含其余结点
+// 只重新链接各层的指针，不复制结点，时间复杂度为O(log n)
+// right 与原跳表使用相同的比较函数、匹配函数、选项和编解码器；
+// 设置了 WithRandSource 时 right 使用由原随机源生成种子的新随机源，两个跳表可以分别在不同的goroutine中使用
+func (l *SkipList[K, V]) Split(key K) (left, right *SkipList[K, V]) {
+	right = &SkipList[K, V]{
+		head:       &Node[K, V]{levels: make([]skipLevel[K, V], l.opts.maxLevel)},
+		cmp:        l.cmp,
+		match:      l.match,
+		opts:       l.opts,
+		keyCodec:   l.keyCodec,
+		valueCodec: l.valueCodec,
+	}
+
+	if l.rnd != nil {
+		right.rnd = rand.New(rand.NewSource(l.rnd.Int63()))
+	}
+
+	var path searchPath[K, V]
+	n := l.fillPath(key, false, &path)
+	for i := 0; i < int(l.layer); i++ {
+		pre := path.pres[i]
+		right.head.levels[i] = skipLevel[K, V]{
+			next: pre.levels[i].next,
+			span: path.ranks[i] + pre.levels[i].span - n,
+		}
+		pre.levels[i] = skipLevel[K, V]{span: n - path.ranks[i]}
+	}
+
+	if first := right.head.levels[0].next; first != nil {
+		first.backward = nil
+		right.tail = l.tail
+		l.tail = path.pres[0]
+		if l.tail == l.head {
+			l.tail = nil
+		}
+	}
+	right.len = l.len - int32(n)
+	right.layer = l.layer
+	l.len = int32(n)
+	l.trimLayer()
+	right.trimLayer()
+	l.reset()
+	return l, right
+}
+
+// Merge 把other的所有结点移动到跳表中，other 被清空
+// 两个跳表的key范围不重叠且最大层数相同时，直接首尾相接，时间复杂度为O(log n)；
+// 否则按升序逐个把other的结点链接进来，结点复用不重新分配
+// 非multimap模式下key相同时使用other的value
+func (l *SkipList[K, V]) Merge(other *SkipList[K, V]) {
+	if other == l || other.len == 0 {
+		return
+	}
+	if l.opts.maxLevel == other.opts.maxLevel {
+		switch {
+		case l.len == 0 || l.before(l.tail.key, other.head.levels[0].next.key):
+			l.appendList(other)
+			other.ClearAll()
+			l.reset()
+			return
+		case l.cmp(other.tail.key, l.head.levels[0].next.key) < 0:
+			other.appendList(l)
+			l.head, l.tail, l.len, l.layer = other.head, other.tail, other.len, other.layer
+			other.ClearAll()
+			l.reset()
+			return
+		}
+	}
+
+	var path searchPath[K, V]
+	var rank int
+	node := other.head.levels[0].next
+	other.ClearAll()
+	for first := true; node != nil; first = false {
+		next := node.levels[0].next
+		if first {
+			rank = l.fillPath(node.key, l.opts.duplicates, &path)
+		} else {
+			rank = l.fingerPath(node.key, &path)
+		}
+
+		if !l.opts.duplicates {
+			if cur := path.pres[0].levels[0].next; cur != nil && l.cmp(cur.key, node.key) == 0 {
+				l.setValue(cur, rank+1, node.value)
+				node = next
+				continue
+			}
+		}
+		if len(node.levels) > l.opts.maxLevel {
+			node.levels = node.levels[:l.opts.maxLevel]
+		}
+		clear(node.levels)
+		node.backward = nil
+		l.linkNode(&path, rank, node)
+		for level := range node.levels {
+			path.pres[level] = node
+			path.ranks[level] = rank + 1
+		}
+		node = next
+	}
+}
+
+// before a 是否可以排在 b 之前：multimap模式下相等的key按插入顺序排列
+func (l *SkipList[K, V]) before(a, b K) bool {
+	c := l.cmp(a, b)
+	return c < 0 || c == 0 && l.opts.duplicates
+}
+
+// appendList 把other的结点整体接到跳表末尾，调用方保证key有序且other的层数不超过最大层数
+// 调用方负责清空other并通知双方的观察者
+func (l *SkipList[K, V]) appendList(other *SkipList[K, V]) {
+	// 每层最后一个结点及其位置
+	var lasts searchPath[K, V]
+	curNode, rank := l.head, 0
+	for i := int(l.layer) - 1; i >= 0; i-- {
+		for curNode.levels[i].next != nil {
+			rank += curNode.levels[i].span
+			curNode = curNode.levels[i].next
+		}
+		lasts.pres[i] = curNode
+		lasts.ranks[i] = rank
+	}
+
+	layer := max(l.layer, other.layer)
+	for i := 0; i < int(layer); i++ {
+		last, lastRank := l.head, 0
+		if i < int(l.layer) {
+			last, lastRank = lasts.pres[i], lasts.ranks[i]
+		}
+		var next *Node[K, V]
+		span := int(other.len)
+		if i < int(other.layer) {
+			next = other.head.levels[i].next
+			if next != nil {
+				span = other.head.levels[i].span
+			}
+		}
+		last.levels[i] = skipLevel[K, V]{next: next, span: int(l.len) - lastRank + span}
+	}
+
+	if first := other.head.levels[0].next; first != nil {
+		first.backward = l.tail
+		l.tail = other.tail
+	}
+	l.len += other.len
+	l.layer = layer
+}
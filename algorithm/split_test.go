@@ -0,0 +1,138 @@
+package algorithm
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestSkipListSplit(t *testing.T) {
+	l := NewOrdered[int, int]()
+	for _, k := range rand.Perm(500) {
+		l.Insert(k, k)
+	}
+	left, right := l.Split(200)
+	if left != l {
+		t.Fatal("left is not the receiver")
+	}
+	checkSpans(t, left)
+	checkSpans(t, right)
+	if left.Len() != 200 || right.Len() != 300 {
+		t.Fatalf("len = %d/%d, want 200/300", left.Len(), right.Len())
+	}
+	if node, _ := left.GetMax(); node.GetKey() != 199 {
+		t.Fatalf("left max = %d", node.GetKey())
+	}
+	if node, _ := right.GetMin(); node.GetKey() != 200 {
+		t.Fatalf("right min = %d", node.GetKey())
+	}
+	if rank, _ := right.Rank(250); rank != 50 {
+		t.Fatalf("rank in right = %d, want 50", rank)
+	}
+	right.Insert(1000, 1000)
+	left.Delete(0)
+	checkSpans(t, left)
+	checkSpans(t, right)
+
+	_, empty := left.Split(10000)
+	checkSpans(t, empty)
+	if empty.Len() != 0 || left.Len() != 199 {
+		t.Fatalf("split past max: %d/%d", left.Len(), empty.Len())
+	}
+	_, all := left.Split(-1)
+	checkSpans(t, left)
+	checkSpans(t, all)
+	if left.Len() != 0 || all.Len() != 199 {
+		t.Fatalf("split before min: %d/%d", left.Len(), all.Len())
+	}
+}
+
+func TestSkipListSplitRandSource(t *testing.T) {
+	l := NewOrdered[int, int](WithRandSource(rand.NewSource(1)))
+	for i := 0; i < 100; i++ {
+		l.Insert(i, i)
+	}
+	left, right := l.Split(50)
+	if right.rnd == nil || right.rnd == left.rnd {
+		t.Fatal("right shares the random source of left")
+	}
+	// 两个跳表分别在不同的goroutine中插入，-race 下不能报告数据竞争
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 100; i < 200; i++ {
+			right.Insert(i, i)
+		}
+	}()
+	for i := -100; i < 0; i++ {
+		left.Insert(i, i)
+	}
+	<-done
+	checkSpans(t, left)
+	checkSpans(t, right)
+}
+
+func TestSkipListMerge(t *testing.T) {
+	build := func(keys []int, opts ...Option) *SkipList[int, int] {
+		l := NewOrdered[int, int](opts...)
+		for _, k := range keys {
+			l.Insert(k, k)
+		}
+		return l
+	}
+	seq := func(lo, hi, step int) []int {
+		var ret []int
+		for i := lo; i < hi; i += step {
+			ret = append(ret, i)
+		}
+		return ret
+	}
+
+	// 不重叠，追加到末尾
+	a, b := build(seq(0, 300, 1)), build(seq(300, 600, 1))
+	a.Merge(b)
+	checkSpans(t, a)
+	checkSpans(t, b)
+	if !equalInts(a.Sort(), seq(0, 600, 1)) || b.Len() != 0 {
+		t.Fatal("append merge failed")
+	}
+
+	// 不重叠，插到开头
+	a, b = build(seq(300, 600, 1)), build(seq(0, 300, 1))
+	a.Merge(b)
+	checkSpans(t, a)
+	if !equalInts(a.Sort(), seq(0, 600, 1)) || b.Len() != 0 {
+		t.Fatal("prepend merge failed")
+	}
+	b.Insert(1, 1)
+	checkSpans(t, b)
+
+	// 重叠，相同的key使用other的value
+	a, b = build(seq(0, 600, 2)), build(seq(0, 600, 3))
+	b.Insert(6, -6)
+	a.Merge(b)
+	checkSpans(t, a)
+	if a.Len() != 400 {
+		t.Fatalf("len = %d, want 400", a.Len())
+	}
+	if node, _ := a.Find(6, 0); node.GetValue() != -6 {
+		t.Fatalf("value of 6 = %d, want -6", node.GetValue())
+	}
+
+	// 最大层数不同时逐个链接
+	a, b = build(seq(0, 100, 1), WithMaxLevel(4)), build(seq(100, 1100, 1))
+	a.Merge(b)
+	checkSpans(t, a)
+	if a.Len() != 1100 || a.layer > 4 {
+		t.Fatalf("len = %d, layer = %d", a.Len(), a.layer)
+	}
+
+	// multimap模式下相等的key排在原有结点之后
+	a, b = build([]int{1, 2}, WithDuplicates()), build([]int{2, 3}, WithDuplicates())
+	a.Insert(2, 20)
+	b.Insert(2, 200)
+	a.Merge(b)
+	checkSpans(t, a)
+	if !equalInts(a.FindAll(2), []int{2, 20, 2, 200}) {
+		t.Fatalf("FindAll(2) = %v", a.FindAll(2))
+	}
+}
@@ -0,0 +1,129 @@
+package algorithm
+
+import (
+	"cmp"
+	"time"
+)
+
+// TimerID 定时器句柄
+type TimerID uint64
+
+// TimerEntry 到期的定时器
+type TimerEntry[T any] struct {
+	ID       TimerID
+	Deadline time.Time
+	Value    T
+}
+
+// timerKey 定时器跳表的索引，按到期时间排序，时间相同按句柄（创建顺序）排序
+type timerKey struct {
+	deadline time.Time
+	id       TimerID
+}
+
+func compareTimerKey(a, b timerKey) int {
+	if c := a.deadline.Compare(b.deadline); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.id, b.id)
+}
+
+// TimerQueue 按到期时间排序的定时器队列，可用作延迟队列
+// 用跳表维护到期顺序，用句柄修改或取消定时器，非并发安全
+type TimerQueue[T any] struct {
+	clock     Clock
+	list      *SkipList[timerKey, T]
+	deadlines map[TimerID]time.Time
+	seq       TimerID
+}
+
+// NewTimerQueue 创建定时器队列，clock 为nil时使用系统时钟
+func NewTimerQueue[T any](clock Clock) *TimerQueue[T] {
+	if clock == nil {
+		clock = SystemClock
+	}
+	return &TimerQueue[T]{
+		clock:     clock,
+		list:      New[timerKey, T](compareTimerKey),
+		deadlines: make(map[TimerID]time.Time),
+	}
+}
+
+// Len 未到期的定时器个数
+func (q *TimerQueue[T]) Len() int {
+	return len(q.deadlines)
+}
+
+// Schedule 添加在deadline到期的定时器，返回句柄
+func (q *TimerQueue[T]) Schedule(deadline time.Time, value T) TimerID {
+	q.seq++
+	q.deadlines[q.seq] = deadline
+	q.list.Insert(timerKey{deadline: deadline, id: q.seq}, value)
+	return q.seq
+}
+
+// After 添加在d之后到期的定时器，返回句柄
+func (q *TimerQueue[T]) After(d time.Duration, value T) TimerID {
+	return q.Schedule(q.clock.Now().Add(d), value)
+}
+
+// Reschedule 修改定时器的到期时间，定时器不存在时返回false
+func (q *TimerQueue[T]) Reschedule(id TimerID, deadline time.Time) bool {
+	old, exist := q.deadlines[id]
+	if !exist {
+		return false
+	}
+	node, _ := q.list.Find(timerKey{deadline: old, id: id}, 0)
+	value := node.GetValue()
+	q.list.Delete(timerKey{deadline: old, id: id})
+	q.list.Insert(timerKey{deadline: deadline, id: id}, value)
+	q.deadlines[id] = deadline
+	return true
+}
+
+// Cancel 取消定时器，返回定时器的value
+func (q *TimerQueue[T]) Cancel(id TimerID) (value T, ok bool) {
+	deadline, exist := q.deadlines[id]
+	if !exist {
+		return
+	}
+	key := timerKey{deadline: deadline, id: id}
+	node, _ := q.list.Find(key, 0)
+	value = node.GetValue()
+	q.list.Delete(key)
+	delete(q.deadlines, id)
+	return value, true
+}
+
+// Deadline 返回定时器的到期时间
+func (q *TimerQueue[T]) Deadline(id TimerID) (time.Time, bool) {
+	deadline, exist := q.deadlines[id]
+	return deadline, exist
+}
+
+// NextDeadline 返回最早的到期时间
+func (q *TimerQueue[T]) NextDeadline() (time.Time, bool) {
+	node, ok := q.list.GetMin()
+	if !ok {
+		return time.Time{}, false
+	}
+	return node.key.deadline, true
+}
+
+// PopExpired 移除并返回到期时间不晚于now的定时器，按到期时间升序排列
+func (q *TimerQueue[T]) PopExpired(now time.Time) []TimerEntry[T] {
+	var ret []TimerEntry[T]
+	for node := q.list.head.levels[0].next; node != nil && !node.key.deadline.After(now); node = node.levels[0].next {
+		ret = append(ret, TimerEntry[T]{ID: node.key.id, Deadline: node.key.deadline, Value: node.value})
+		delete(q.deadlines, node.key.id)
+	}
+	if len(ret) > 0 {
+		q.list.DeleteRangeByRank(0, len(ret)-1)
+	}
+	return ret
+}
+
+// Expired 移除并返回按时钟当前时间已到期的定时器
+func (q *TimerQueue[T]) Expired() []TimerEntry[T] {
+	return q.PopExpired(q.clock.Now())
+}
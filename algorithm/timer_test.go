@@ -0,0 +1,75 @@
+package algorithm
+
+import (
+	"testing"
+	"time"
+)
+
+func timerValues(entries []TimerEntry[string]) []string {
+	var ret []string
+	for _, e := range entries {
+		ret = append(ret, e.Value)
+	}
+	return ret
+}
+
+func TestTimerQueue(t *testing.T) {
+	clock := newFakeClock()
+	q := NewTimerQueue[string](clock)
+	a := q.After(3*time.Second, "a")
+	q.After(time.Second, "b")
+	c := q.After(time.Second, "c")
+	d := q.After(5*time.Second, "d")
+	if q.Len() != 4 {
+		t.Fatalf("len = %d, want 4", q.Len())
+	}
+	if next, ok := q.NextDeadline(); !ok || !next.Equal(clock.now.Add(time.Second)) {
+		t.Fatalf("next deadline = %v, %v", next, ok)
+	}
+	if got := q.Expired(); len(got) != 0 {
+		t.Fatalf("expired before deadline: %v", got)
+	}
+
+	if !q.Reschedule(a, clock.now.Add(500*time.Millisecond)) {
+		t.Fatal("reschedule failed")
+	}
+	if v, ok := q.Cancel(c); !ok || v != "c" {
+		t.Fatalf("cancel = %v, %v", v, ok)
+	}
+	if _, ok := q.Cancel(c); ok {
+		t.Fatal("cancel twice")
+	}
+	if q.Reschedule(c, clock.now) {
+		t.Fatal("reschedule canceled timer")
+	}
+
+	clock.Advance(time.Second)
+	if got := timerValues(q.Expired()); !equalStrings(got, []string{"a", "b"}) {
+		t.Fatalf("expired = %v, want [a b]", got)
+	}
+	if deadline, ok := q.Deadline(d); !ok || !deadline.Equal(clock.now.Add(4*time.Second)) {
+		t.Fatalf("deadline of d = %v, %v", deadline, ok)
+	}
+	if got := timerValues(q.PopExpired(clock.now.Add(time.Hour))); !equalStrings(got, []string{"d"}) {
+		t.Fatalf("expired = %v, want [d]", got)
+	}
+	if _, ok := q.NextDeadline(); ok || q.Len() != 0 {
+		t.Fatal("queue not empty")
+	}
+}
+
+func TestTimerQueueSameDeadline(t *testing.T) {
+	clock := newFakeClock()
+	q := NewTimerQueue[string](clock)
+	deadline := clock.now.Add(time.Minute)
+	for _, v := range []string{"x", "y", "z"} {
+		q.Schedule(deadline, v)
+	}
+	entries := q.PopExpired(deadline)
+	if got := timerValues(entries); !equalStrings(got, []string{"x", "y", "z"}) {
+		t.Fatalf("expired = %v, want creation order", got)
+	}
+	if entries[0].ID >= entries[1].ID || !entries[2].Deadline.Equal(deadline) {
+		t.Fatalf("entries = %+v", entries)
+	}
+}
@@ -0,0 +1,449 @@
+package algorithm
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// 预写日志格式，每条记录：
+//
+//	payloadLen(4, 大端) crc32(4, 大端，覆盖payload) payload
+//	payload: op(1) keyLen(uvarint) key [valueLen(uvarint) value]
+//
+// 目录中的文件按代数命名：snap-<gen> 为快照，wal-<gen> 为该快照之后的日志，
+// Checkpoint 写入新一代的快照后切换到新一代的日志，再删除旧的文件
+const (
+	walOpInsert byte = 1
+	walOpDelete byte = 2
+
+	walHeaderSize = 8
+	walSnapPrefix = "snap-"
+	walLogPrefix  = "wal-"
+)
+
+// ErrWALCorrupt 日志记录校验通过但内容无法解析，或校验失败的记录之后还有有效记录
+var ErrWALCorrupt = errors.New("skiplist: corrupted wal record")
+
+// errWALChecksum 单条日志记录校验失败或长度不可信
+var errWALChecksum = errors.New("wal checksum mismatch")
+
+// SyncPolicy 日志的刷盘策略
+type SyncPolicy int
+
+const (
+	// SyncAlways 每次写入后都fsync
+	SyncAlways SyncPolicy = iota
+	// SyncInterval 距上次fsync超过 SyncInterval 时，在写入后fsync
+	SyncInterval
+	// SyncNever 不主动fsync，由操作系统决定何时写盘
+	SyncNever
+)
+
+// DurableConfig 持久化跳表的配置
+type DurableConfig struct {
+	// Dir 存放快照和日志的目录，不存在时自动创建
+	Dir string
+	// Sync 刷盘策略，默认为 SyncAlways
+	Sync         SyncPolicy
+	SyncInterval time.Duration
+	// Clock 时钟，用于 SyncInterval，默认为系统时钟
+	Clock Clock
+}
+
+// walFile 日志文件，测试中用于注入写入错误
+type walFile interface {
+	io.ReadWriteSeeker
+	Truncate(size int64) error
+	Sync() error
+	Close() error
+}
+
+// DurableSkipList 带预写日志的跳表
+// 每次 Insert/Delete 先追加到日志，写入成功后再修改跳表；
+// 打开时加载最新的快照并重放日志，日志末尾不完整或校验失败的记录会被截断，
+// 日志中间的记录损坏时返回 ErrWALCorrupt，不修改日志
+// 非并发安全
+type DurableSkipList[K, V any] struct {
+	list     *SkipList[K, V]
+	cfg      DurableConfig
+	gen      uint64
+	wal      walFile
+	lastSync time.Time
+	buf      []byte
+	size     int64 // 日志中有效记录的长度
+	failed   error // 回滚写入失败的记录也失败时，之后的写入都返回该错误
+}
+
+// OpenDurable 打开dir中的快照和日志恢复跳表l，l 需要先通过 SetCodec 设置编解码器
+// l 原有的结点会被清空，之后应当只通过返回的 DurableSkipList 修改
+func OpenDurable[K, V any](l *SkipList[K, V], cfg DurableConfig) (*DurableSkipList[K, V], error) {
+	if l.keyCodec == nil || l.valueCodec == nil {
+		return nil, ErrNoCodec
+	}
+	if cfg.Clock == nil {
+		cfg.Clock = SystemClock
+	}
+	if err := os.MkdirAll(cfg.Dir, 0o755); err != nil {
+		return nil, err
+	}
+	d := &DurableSkipList[K, V]{list: l, cfg: cfg}
+	if err := d.recover(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// List 返回内存中的跳表，只能用于读取
+func (d *DurableSkipList[K, V]) List() *SkipList[K, V] {
+	return d.list
+}
+
+// Insert 写入日志后插入结点
+// 返回错误时跳表不会被修改；但如果只是刷盘失败，记录已经写入日志，恢复时仍可能被重放
+func (d *DurableSkipList[K, V]) Insert(key K, value V) error {
+	kb, err := d.list.keyCodec.Encode(key)
+	if err != nil {
+		return err
+	}
+	vb, err := d.list.valueCodec.Encode(value)
+	if err != nil {
+		return err
+	}
+	if err = d.append(walOpInsert, kb, vb); err != nil {
+		return err
+	}
+	d.list.Insert(key, value)
+	return nil
+}
+
+// Delete 写入日志后删除结点
+// 返回错误时跳表不会被修改；但如果只是刷盘失败，记录已经写入日志，恢复时仍可能被重放
+func (d *DurableSkipList[K, V]) Delete(key K) error {
+	kb, err := d.list.keyCodec.Encode(key)
+	if err != nil {
+		return err
+	}
+	if err = d.append(walOpDelete, kb, nil); err != nil {
+		return err
+	}
+	d.list.Delete(key)
+	return nil
+}
+
+// Sync 立即将日志刷盘
+func (d *DurableSkipList[K, V]) Sync() error {
+	d.lastSync = d.cfg.Clock.Now()
+	return d.wal.Sync()
+}
+
+// Checkpoint 写入新的快照并清空日志，恢复时只需加载快照
+func (d *DurableSkipList[K, V]) Checkpoint() error {
+	gen := d.gen + 1
+	if err := d.writeSnapshot(gen); err != nil {
+		return err
+	}
+	wal, err := os.OpenFile(d.path(walLogPrefix, gen), os.O_CREATE|os.O_TRUNC|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	old := d.wal
+	d.wal, d.gen, d.size, d.failed = wal, gen, 0, nil
+	old.Close()
+	// 新的快照和日志在目录中持久化之后才能删除旧的文件，否则崩溃时可能两代都丢失
+	if err = syncDir(d.cfg.Dir); err != nil {
+		return err
+	}
+	d.removeBefore(gen)
+	return nil
+}
+
+// Close 刷盘并关闭日志
+func (d *DurableSkipList[K, V]) Close() error {
+	err := d.wal.Sync()
+	if cerr := d.wal.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// append 追加一条日志记录，并按刷盘策略fsync
+// 写入失败时把日志截断回写入前的长度，避免不完整的记录之后再追加新的记录
+func (d *DurableSkipList[K, V]) append(op byte, key, value []byte) error {
+	if d.failed != nil {
+		return d.failed
+	}
+	buf := append(d.buf[:0], make([]byte, walHeaderSize)...)
+	buf = append(buf, op)
+	buf = binary.AppendUvarint(buf, uint64(len(key)))
+	buf = append(buf, key...)
+	if op == walOpInsert {
+		buf = binary.AppendUvarint(buf, uint64(len(value)))
+		buf = append(buf, value...)
+	}
+	payload := buf[walHeaderSize:]
+	binary.BigEndian.PutUint32(buf[0:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(buf[4:8], crc32.ChecksumIEEE(payload))
+	d.buf = buf
+
+	if _, err := d.wal.Write(buf); err != nil {
+		if terr := d.rollback(); terr != nil {
+			d.failed = fmt.Errorf("rollback wal after %v: %w", err, terr)
+		}
+		return err
+	}
+	d.size += int64(len(buf))
+	switch d.cfg.Sync {
+	case SyncAlways:
+		return d.Sync()
+	case SyncInterval:
+		if d.cfg.Clock.Now().Sub(d.lastSync) >= d.cfg.SyncInterval {
+			return d.Sync()
+		}
+	}
+	return nil
+}
+
+// rollback 丢弃日志中有效记录之后的数据
+func (d *DurableSkipList[K, V]) rollback() error {
+	if err := d.wal.Truncate(d.size); err != nil {
+		return err
+	}
+	_, err := d.wal.Seek(d.size, io.SeekStart)
+	return err
+}
+
+// recover 加载最新的快照并重放对应的日志
+func (d *DurableSkipList[K, V]) recover() error {
+	entries, err := os.ReadDir(d.cfg.Dir)
+	if err != nil {
+		return err
+	}
+	hasSnap := false
+	for _, e := range entries {
+		if gen, ok := parseGen(e.Name(), walSnapPrefix); ok && (!hasSnap || gen > d.gen) {
+			d.gen, hasSnap = gen, true
+		}
+	}
+
+	if hasSnap {
+		f, err := os.Open(d.path(walSnapPrefix, d.gen))
+		if err != nil {
+			return err
+		}
+		_, err = d.list.ReadFrom(bufio.NewReader(f))
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("load snapshot %d: %w", d.gen, err)
+		}
+	} else {
+		d.list.ClearAll()
+	}
+
+	wal, err := os.OpenFile(d.path(walLogPrefix, d.gen), os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return err
+	}
+	d.wal = wal
+	if err = d.replay(); err != nil {
+		d.wal.Close()
+		return err
+	}
+	d.lastSync = d.cfg.Clock.Now()
+	d.removeBefore(d.gen)
+	return nil
+}
+
+// replay 重放日志，截断末尾不完整或校验失败的记录，之后的写入追加在有效记录之后
+// 只有损坏的记录之后再没有有效记录时才认为是写入中断造成的，
+// 否则（例如中间记录的长度被改写）返回 ErrWALCorrupt，日志文件保持不变
+func (d *DurableSkipList[K, V]) replay() error {
+	br := bufio.NewReader(d.wal)
+	var offset int64
+	for {
+		payload, err := readWALRecord(br)
+		if err == io.EOF {
+			break
+		}
+		if err == io.ErrUnexpectedEOF || err == errWALChecksum {
+			next, err := d.findRecord(offset + 1)
+			if err != nil {
+				return err
+			}
+			if next >= 0 {
+				return fmt.Errorf("%w at offset %d: valid record at offset %d", ErrWALCorrupt, offset, next)
+			}
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if err = d.apply(payload); err != nil {
+			return fmt.Errorf("%w at offset %d: %v", ErrWALCorrupt, offset, err)
+		}
+		offset += walHeaderSize + int64(len(payload))
+	}
+
+	d.size = offset
+	return d.rollback()
+}
+
+// readWALRecord 读取一条日志记录，读到文件末尾仍不完整时返回 io.EOF 或 io.ErrUnexpectedEOF
+func readWALRecord(r io.Reader) ([]byte, error) {
+	var header [walHeaderSize]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, err
+	}
+	size := binary.BigEndian.Uint32(header[0:4])
+	if size > snapshotMaxField {
+		// 长度不可信，由调用者检查之后是否还有有效记录
+		return nil, errWALChecksum
+	}
+	payload := make([]byte, size)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	if crc32.ChecksumIEEE(payload) != binary.BigEndian.Uint32(header[4:8]) {
+		return payload, errWALChecksum
+	}
+	return payload, nil
+}
+
+// findRecord 从offset开始查找第一条校验通过的记录，返回其偏移，找不到时返回-1
+// 损坏的长度无法定位下一条记录，所以逐字节查找；有效记录至少包含op和keyLen，
+// 长度为0的头部（例如补零的文件末尾）不算有效记录
+func (d *DurableSkipList[K, V]) findRecord(offset int64) (int64, error) {
+	if _, err := d.wal.Seek(offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+	rest, err := io.ReadAll(d.wal)
+	if err != nil {
+		return 0, err
+	}
+	for i := 0; i+walHeaderSize <= len(rest); i++ {
+		size := uint64(binary.BigEndian.Uint32(rest[i:]))
+		if size < 2 || size > uint64(len(rest)-i-walHeaderSize) {
+			continue
+		}
+		payload := rest[i+walHeaderSize : i+walHeaderSize+int(size)]
+		if crc32.ChecksumIEEE(payload) == binary.BigEndian.Uint32(rest[i+4:]) {
+			return offset + int64(i), nil
+		}
+	}
+	return -1, nil
+}
+
+// apply 把一条日志记录应用到跳表
+func (d *DurableSkipList[K, V]) apply(payload []byte) error {
+	if len(payload) == 0 {
+		return ErrCodecData
+	}
+	op, rest := payload[0], payload[1:]
+	kb, rest, err := walField(rest)
+	if err != nil {
+		return err
+	}
+	key, err := d.list.keyCodec.Decode(kb)
+	if err != nil {
+		return err
+	}
+	switch op {
+	case walOpInsert:
+		vb, _, err := walField(rest)
+		if err != nil {
+			return err
+		}
+		value, err := d.list.valueCodec.Decode(vb)
+		if err != nil {
+			return err
+		}
+		d.list.Insert(key, value)
+	case walOpDelete:
+		d.list.Delete(key)
+	default:
+		return fmt.Errorf("unknown op %d", op)
+	}
+	return nil
+}
+
+// writeSnapshot 先写入临时文件并fsync，再重命名为第gen代快照
+func (d *DurableSkipList[K, V]) writeSnapshot(gen uint64) error {
+	path := d.path(walSnapPrefix, gen)
+	f, err := os.Create(path + ".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err = d.list.WriteTo(f); err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(path+".tmp", path)
+	}
+	if err != nil {
+		os.Remove(path + ".tmp")
+	}
+	return err
+}
+
+// removeBefore 删除早于gen代的快照和日志
+func (d *DurableSkipList[K, V]) removeBefore(gen uint64) {
+	entries, err := os.ReadDir(d.cfg.Dir)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		for _, prefix := range []string{walSnapPrefix, walLogPrefix} {
+			if g, ok := parseGen(e.Name(), prefix); ok && g < gen {
+				os.Remove(filepath.Join(d.cfg.Dir, e.Name()))
+			}
+		}
+	}
+}
+
+func (d *DurableSkipList[K, V]) path(prefix string, gen uint64) string {
+	return filepath.Join(d.cfg.Dir, fmt.Sprintf("%s%016d", prefix, gen))
+}
+
+// parseGen 解析文件名中的代数
+func parseGen(name, prefix string) (uint64, bool) {
+	if !strings.HasPrefix(name, prefix) || strings.HasSuffix(name, ".tmp") {
+		return 0, false
+	}
+	var gen uint64
+	if _, err := fmt.Sscanf(name[len(prefix):], "%d", &gen); err != nil {
+		return 0, false
+	}
+	return gen, true
+}
+
+// walField 读取带长度前缀的字段，返回字段与剩余的数据
+func walField(data []byte) ([]byte, []byte, error) {
+	size, n := binary.Uvarint(data)
+	if n <= 0 || uint64(len(data)-n) < size {
+		return nil, nil, ErrCodecData
+	}
+	return data[n : n+int(size)], data[n+int(size):], nil
+}
+
+// syncDir fsync目录，保证重命名和新建的文件持久化
+func syncDir(dir string) error {
+	f, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return f.Sync()
+}
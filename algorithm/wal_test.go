@@ -0,0 +1,229 @@
+package algorithm
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func openDurableInts(t *testing.T, dir string, cfg DurableConfig) *DurableSkipList[int, string] {
+	t.Helper()
+	l := NewOrdered[int, string]()
+	l.SetCodec(IntCodec[int]{}, StringCodec{})
+	cfg.Dir = dir
+	d, err := OpenDurable(l, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func durableKeys(d *DurableSkipList[int, string]) []int {
+	var keys []int
+	for k := range d.List().All() {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+func TestDurableSkipListRecover(t *testing.T) {
+	dir := t.TempDir()
+	d := openDurableInts(t, dir, DurableConfig{})
+	for i := 0; i < 10; i++ {
+		if err := d.Insert(i, "v"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	d.Insert(3, "three")
+	d.Delete(5)
+	if err := d.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	d = openDurableInts(t, dir, DurableConfig{})
+	if !equalInts(durableKeys(d), []int{0, 1, 2, 3, 4, 6, 7, 8, 9}) {
+		t.Fatalf("keys = %v", durableKeys(d))
+	}
+	if node, _ := d.List().Find(3, 0); node.GetValue() != "three" {
+		t.Fatalf("value of 3 = %q", node.GetValue())
+	}
+
+	// 检查点之后日志被清空，旧的文件被删除
+	if err := d.Checkpoint(); err != nil {
+		t.Fatal(err)
+	}
+	d.Insert(100, "x")
+	d.Close()
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 2 {
+		t.Fatalf("files after checkpoint = %d, want 2", len(entries))
+	}
+	d = openDurableInts(t, dir, DurableConfig{Sync: SyncNever})
+	defer d.Close()
+	if d.List().Len() != 10 {
+		t.Fatalf("len = %d, want 10", d.List().Len())
+	}
+}
+
+func TestDurableSkipListTornTail(t *testing.T) {
+	dir := t.TempDir()
+	d := openDurableInts(t, dir, DurableConfig{})
+	d.Insert(1, "a")
+	d.Insert(2, "b")
+	d.Close()
+	walPath := filepath.Join(dir, "wal-0000000000000000")
+	info, _ := os.Stat(walPath)
+	good := info.Size()
+
+	// 末尾写入不完整的记录
+	f, _ := os.OpenFile(walPath, os.O_APPEND|os.O_WRONLY, 0)
+	f.Write([]byte{0, 0, 0, 9, 1, 2})
+	f.Close()
+	d = openDurableInts(t, dir, DurableConfig{})
+	if !equalInts(durableKeys(d), []int{1, 2}) {
+		t.Fatalf("keys = %v", durableKeys(d))
+	}
+	if info, _ := os.Stat(walPath); info.Size() != good {
+		t.Fatalf("wal size = %d, want %d", info.Size(), good)
+	}
+	d.Insert(3, "c")
+	d.Close()
+
+	// 最后一条记录校验失败
+	data, _ := os.ReadFile(walPath)
+	data[len(data)-1] ^= 0xff
+	os.WriteFile(walPath, data, 0o644)
+	d = openDurableInts(t, dir, DurableConfig{})
+	defer d.Close()
+	if !equalInts(durableKeys(d), []int{1, 2}) {
+		t.Fatalf("keys after bad checksum = %v", durableKeys(d))
+	}
+	d.Insert(4, "d")
+	if !equalInts(durableKeys(d), []int{1, 2, 4}) {
+		t.Fatalf("keys = %v", durableKeys(d))
+	}
+}
+
+func TestDurableSkipListCorruptMiddle(t *testing.T) {
+	dir := t.TempDir()
+	d := openDurableInts(t, dir, DurableConfig{})
+	d.Insert(1, "a")
+	d.Insert(2, "b")
+	d.Insert(3, "c")
+	d.Close()
+
+	// 第一条记录校验失败，之后的记录有效，不能静默截断
+	walPath := filepath.Join(dir, "wal-0000000000000000")
+	data, _ := os.ReadFile(walPath)
+	data[walHeaderSize] ^= 0xff
+	os.WriteFile(walPath, data, 0o644)
+	l := NewOrdered[int, string]()
+	l.SetCodec(IntCodec[int]{}, StringCodec{})
+	if _, err := OpenDurable(l, DurableConfig{Dir: dir}); !errors.Is(err, ErrWALCorrupt) {
+		t.Fatalf("err = %v, want ErrWALCorrupt", err)
+	}
+	if info, _ := os.Stat(walPath); info.Size() != int64(len(data)) {
+		t.Fatalf("wal truncated to %d", info.Size())
+	}
+}
+
+func TestDurableSkipListCorruptHeader(t *testing.T) {
+	dir := t.TempDir()
+	d := openDurableInts(t, dir, DurableConfig{})
+	for i := 1; i <= 5; i++ {
+		d.Insert(i, "v")
+	}
+	d.Close()
+	walPath := filepath.Join(dir, "wal-0000000000000000")
+	orig, _ := os.ReadFile(walPath)
+	first := walHeaderSize + int(orig[3])
+
+	// 第二条记录的长度或校验和被改写，之后的记录仍然有效
+	for _, pos := range []int{0, 2, 3, 5} {
+		data := append([]byte(nil), orig...)
+		data[first+pos] ^= 0x40
+		os.WriteFile(walPath, data, 0o644)
+		l := NewOrdered[int, string]()
+		l.SetCodec(IntCodec[int]{}, StringCodec{})
+		if _, err := OpenDurable(l, DurableConfig{Dir: dir}); !errors.Is(err, ErrWALCorrupt) {
+			t.Fatalf("header byte %d: err = %v, want ErrWALCorrupt", pos, err)
+		}
+		if got, _ := os.ReadFile(walPath); string(got) != string(data) {
+			t.Fatalf("header byte %d: wal modified, size %d -> %d", pos, len(data), len(got))
+		}
+	}
+}
+
+// shortWriter 只写入一半数据后返回错误
+type shortWriter struct {
+	walFile
+	fail bool
+}
+
+func (w *shortWriter) Write(p []byte) (int, error) {
+	if !w.fail {
+		return w.walFile.Write(p)
+	}
+	w.fail = false
+	n, _ := w.walFile.Write(p[:len(p)/2])
+	return n, errors.New("disk full")
+}
+
+func TestDurableSkipListWriteRollback(t *testing.T) {
+	dir := t.TempDir()
+	d := openDurableInts(t, dir, DurableConfig{})
+	d.Insert(1, "a")
+	w := &shortWriter{walFile: d.wal, fail: true}
+	d.wal = w
+	if err := d.Insert(2, "b"); err == nil {
+		t.Fatal("short write succeeded")
+	}
+	if d.List().Len() != 1 {
+		t.Fatalf("len = %d after failed insert", d.List().Len())
+	}
+	// 失败的记录被回滚，之后写入的记录在恢复时不会丢失
+	if err := d.Insert(3, "c"); err != nil {
+		t.Fatal(err)
+	}
+	d.Close()
+	d = openDurableInts(t, dir, DurableConfig{})
+	defer d.Close()
+	if !equalInts(durableKeys(d), []int{1, 3}) {
+		t.Fatalf("keys = %v", durableKeys(d))
+	}
+}
+
+func TestDurableSkipListDuplicates(t *testing.T) {
+	dir := t.TempDir()
+	open := func() *DurableSkipList[int, string] {
+		l := NewOrdered[int, string](WithDuplicates())
+		l.SetCodec(IntCodec[int]{}, StringCodec{})
+		d, err := OpenDurable(l, DurableConfig{Dir: dir, Sync: SyncInterval, SyncInterval: time.Second, Clock: newFakeClock()})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+	d := open()
+	d.Insert(1, "a")
+	d.Insert(1, "b")
+	d.Checkpoint()
+	d.Insert(1, "c")
+	d.Delete(1)
+	d.Close()
+
+	d = open()
+	defer d.Close()
+	if got := d.List().FindAll(1); !equalStrings(got, []string{"b", "c"}) {
+		t.Fatalf("FindAll(1) = %v", got)
+	}
+}
+
+func TestOpenDurableNoCodec(t *testing.T) {
+	_, err := OpenDurable(NewOrdered[int, int](), DurableConfig{Dir: t.TempDir()})
+	if !errors.Is(err, ErrNoCodec) {
+		t.Fatalf("err = %v, want ErrNoCodec", err)
+	}
+}
@@ -0,0 +1,210 @@
+package algorithm
+
+import (
+	"cmp"
+	"sync"
+	"time"
+)
+
+// ResetPeriod 排行榜定期清空的周期
+type ResetPeriod int
+
+const (
+	// ResetNone 不定期清空
+	ResetNone ResetPeriod = iota
+	// ResetDaily 每天0点清空
+	ResetDaily
+	// ResetWeekly 每周一0点清空
+	ResetWeekly
+)
+
+// WindowConfig 时间窗口排行榜配置
+type WindowConfig struct {
+	// Window 滑动窗口的长度，只统计最近 Window 内的分数，0 表示不限制
+	Window time.Duration
+	// Buckets 窗口划分的桶数，分数按桶过期，默认60
+	Buckets int
+	// Reset 定期清空的周期
+	Reset ResetPeriod
+	// Location 计算每天/每周起点的时区，默认为UTC
+	Location *time.Location
+	// Clock 时钟，默认为系统时钟
+	Clock Clock
+}
+
+// scoreBucket 一个时间桶内各成员增加的分数
+type scoreBucket[M cmp.Ordered] struct {
+	index  int64
+	scores map[M]float64
+}
+
+// WindowRank 时间窗口排行榜，成员的分数为窗口内增加的分数之和，可以并发使用
+// 分数按时间分桶记录，桶移出滑动窗口时从总分中扣除；到达每天/每周的起点时整体清空
+// 排名按分数降序，由 ZSet 维护
+type WindowRank[M cmp.Ordered] struct {
+	mu      sync.Mutex
+	cfg     WindowConfig
+	width   time.Duration // 每个桶的时长
+	buckets []*scoreBucket[M]
+	refs    map[M]int // 成员出现在多少个桶中
+	zset    *ZSet[M]
+	period  time.Time // 当前周期的起点
+}
+
+// NewWindowRank 创建时间窗口排行榜
+func NewWindowRank[M cmp.Ordered](cfg WindowConfig) *WindowRank[M] {
+	if cfg.Buckets <= 0 {
+		cfg.Buckets = 60
+	}
+	if cfg.Location == nil {
+		cfg.Location = time.UTC
+	}
+	if cfg.Clock == nil {
+		cfg.Clock = SystemClock
+	}
+	w := &WindowRank[M]{
+		cfg:  cfg,
+		refs: make(map[M]int),
+		zset: NewZSet[M](),
+	}
+	if cfg.Window > 0 {
+		w.width = cfg.Window / time.Duration(cfg.Buckets)
+		if w.width <= 0 {
+			w.width = 1
+		}
+	}
+	w.period = w.periodStart(cfg.Clock.Now())
+	return w
+}
+
+// Add 成员增加分数，返回成员在窗口内的总分
+func (w *WindowRank[M]) Add(member M, score float64) (float64, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	now := w.cfg.Clock.Now()
+	w.advance(now)
+
+	total, err := w.zset.ZIncrBy(score, member)
+	if err != nil {
+		return total, err
+	}
+	if w.width > 0 {
+		b := w.current(now)
+		if _, exist := b.scores[member]; !exist {
+			w.refs[member]++
+		}
+		b.scores[member] += score
+	}
+	return total, nil
+}
+
+// TopK 返回窗口内分数最高的k个成员，按分数降序排列
+// 与 ZSet.ZRevRange 相同，分数相同的成员按成员降序排列，与 Rank 的排名一致
+func (w *WindowRank[M]) TopK(k int) []ZMember[M] {
+	if k <= 0 {
+		return nil
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.advance(w.cfg.Clock.Now())
+	return w.zset.ZRevRange(0, k-1)
+}
+
+// Rank 返回成员按分数降序的排名，从0开始，分数相同时成员较大的排在前面
+func (w *WindowRank[M]) Rank(member M) (int, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.advance(w.cfg.Clock.Now())
+	return w.zset.ZRevRank(member)
+}
+
+// Score 返回成员在窗口内的总分
+func (w *WindowRank[M]) Score(member M) (float64, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.advance(w.cfg.Clock.Now())
+	return w.zset.ZScore(member)
+}
+
+// Len 窗口内有分数的成员个数
+func (w *WindowRank[M]) Len() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.advance(w.cfg.Clock.Now())
+	return w.zset.ZCard()
+}
+
+// Reset 立即清空排行榜
+func (w *WindowRank[M]) Reset() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.clear()
+}
+
+// advance 到达新的周期时清空，并扣除移出窗口的桶
+func (w *WindowRank[M]) advance(now time.Time) {
+	if period := w.periodStart(now); !period.Equal(w.period) {
+		w.period = period
+		w.clear()
+		return
+	}
+	if w.width <= 0 {
+		return
+	}
+	oldest := w.bucketIndex(now) - int64(w.cfg.Buckets)
+	expired := 0
+	for _, b := range w.buckets {
+		if b.index > oldest {
+			break
+		}
+		for member, score := range b.scores {
+			w.refs[member]--
+			if w.refs[member] == 0 {
+				// 最后一个桶过期时直接删除，避免浮点误差留下接近0的分数
+				delete(w.refs, member)
+				w.zset.ZRem(member)
+				continue
+			}
+			w.zset.ZIncrBy(-score, member)
+		}
+		expired++
+	}
+	if expired > 0 {
+		w.buckets = append(w.buckets[:0], w.buckets[expired:]...)
+	}
+}
+
+// current 返回now所在的桶
+func (w *WindowRank[M]) current(now time.Time) *scoreBucket[M] {
+	index := w.bucketIndex(now)
+	if n := len(w.buckets); n > 0 && w.buckets[n-1].index == index {
+		return w.buckets[n-1]
+	}
+	b := &scoreBucket[M]{index: index, scores: make(map[M]float64)}
+	w.buckets = append(w.buckets, b)
+	return b
+}
+
+func (w *WindowRank[M]) bucketIndex(now time.Time) int64 {
+	return now.UnixNano() / int64(w.width)
+}
+
+func (w *WindowRank[M]) clear() {
+	w.buckets = nil
+	w.refs = make(map[M]int)
+	w.zset = NewZSet[M]()
+}
+
+// periodStart 返回now所在周期的起点，不定期清空时返回零值
+func (w *WindowRank[M]) periodStart(now time.Time) time.Time {
+	if w.cfg.Reset == ResetNone {
+		return time.Time{}
+	}
+	y, m, d := now.In(w.cfg.Location).Date()
+	start := time.Date(y, m, d, 0, 0, 0, 0, w.cfg.Location)
+	if w.cfg.Reset == ResetWeekly {
+		// 以周一为每周的第一天
+		start = start.AddDate(0, 0, -(int(start.Weekday())+6)%7)
+	}
+	return start
+}
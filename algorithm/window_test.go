@@ -0,0 +1,88 @@
+package algorithm
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWindowRankSliding(t *testing.T) {
+	clock := newFakeClock()
+	w := NewWindowRank[string](WindowConfig{Window: time.Hour, Buckets: 6, Clock: clock})
+	w.Add("a", 10)
+	w.Add("b", 5)
+	clock.Advance(30 * time.Minute)
+	w.Add("b", 10)
+	w.Add("c", 1)
+
+	top := w.TopK(2)
+	if len(top) != 2 || top[0].Member != "b" || top[0].Score != 15 || top[1].Member != "a" {
+		t.Fatalf("top = %v", top)
+	}
+	if rank, ok := w.Rank("c"); !ok || rank != 2 {
+		t.Fatalf("rank of c = %d, %v", rank, ok)
+	}
+
+	// 第一批分数移出窗口
+	clock.Advance(35 * time.Minute)
+	if _, ok := w.Score("a"); ok {
+		t.Fatal("a not evicted")
+	}
+	if score, _ := w.Score("b"); score != 10 {
+		t.Fatalf("score of b = %v, want 10", score)
+	}
+	if w.Len() != 2 {
+		t.Fatalf("len = %d, want 2", w.Len())
+	}
+	clock.Advance(time.Hour)
+	if w.Len() != 0 || len(w.TopK(10)) != 0 {
+		t.Fatal("window not empty")
+	}
+}
+
+func TestWindowRankReset(t *testing.T) {
+	clock := newFakeClock() // 2022-01-01 是周六
+	daily := NewWindowRank[int](WindowConfig{Reset: ResetDaily, Clock: clock})
+	weekly := NewWindowRank[int](WindowConfig{Reset: ResetWeekly, Clock: clock})
+	for _, w := range []*WindowRank[int]{daily, weekly} {
+		w.Add(1, 3)
+		w.Add(2, 5)
+	}
+
+	clock.Advance(23 * time.Hour)
+	if daily.Len() != 2 {
+		t.Fatal("daily reset too early")
+	}
+	clock.Advance(2 * time.Hour) // 周日
+	if daily.Len() != 0 {
+		t.Fatal("daily not reset")
+	}
+	if weekly.Len() != 2 {
+		t.Fatal("weekly reset too early")
+	}
+	clock.Advance(24 * time.Hour) // 周一
+	if weekly.Len() != 0 {
+		t.Fatal("weekly not reset")
+	}
+
+	weekly.Add(7, 1)
+	weekly.Reset()
+	if _, ok := weekly.Rank(7); ok {
+		t.Fatal("manual reset failed")
+	}
+}
+
+func TestWindowRankTies(t *testing.T) {
+	w := NewWindowRank[string](WindowConfig{Window: time.Hour, Clock: newFakeClock()})
+	for _, m := range []string{"b", "d", "a", "c"} {
+		w.Add(m, 10)
+	}
+	w.Add("e", 20)
+	// 分数相同时按成员降序，与 ZRevRange 一致
+	top := w.TopK(3)
+	if top[0].Member != "e" || top[1].Member != "d" || top[2].Member != "c" {
+		t.Fatalf("top = %v", top)
+	}
+	if rank, _ := w.Rank("a"); rank != 4 {
+		t.Fatalf("rank of a = %d, want 4", rank)
+	}
+}
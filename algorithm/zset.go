@@ -0,0 +1,293 @@
+package algorithm
+
+import (
+	"cmp"
+	"errors"
+	"math"
+)
+
+// ZAddFlag ZAdd 的选项，与redis ZADD 的选项一致
+type ZAddFlag int
+
+const (
+	// ZAddNX 只添加新成员，不更新已存在的成员
+	ZAddNX ZAddFlag = 1 << iota
+	// ZAddXX 只更新已存在的成员，不添加新成员
+	ZAddXX
+	// ZAddGT 只在新分数大于当前分数时更新
+	ZAddGT
+	// ZAddLT 只在新分数小于当前分数时更新
+	ZAddLT
+)
+
+var (
+	// ErrZAddFlags ZAdd 选项冲突
+	ErrZAddFlags = errors.New("zset: GT, LT, and/or NX options at the same time are not compatible")
+	// ErrZScoreNaN 分数不是数字
+	ErrZScoreNaN = errors.New("zset: score is not a number")
+)
+
+// ZMember 有序集合成员
+type ZMember[M cmp.Ordered] struct {
+	Member M
+	Score  float64
+}
+
+// zkey 跳表中的索引，先按分数排序，分数相同按成员排序
+// bound 只用于查找：-1 排在同分数所有成员之前，1 排在之后
+type zkey[M cmp.Ordered] struct {
+	score  float64
+	member M
+	bound  int8
+}
+
+func compareZKey[M cmp.Ordered](a, b zkey[M]) int {
+	if a.score != b.score {
+		return cmp.Compare(a.score, b.score)
+	}
+	if a.bound != 0 || b.bound != 0 {
+		return cmp.Compare(a.bound, b.bound)
+	}
+	return cmp.Compare(a.member, b.member)
+}
+
+// ZSet redis风格的有序集合
+// 用哈希表记录成员分数，用跳表维护排序，分数相同的成员按成员排序
+type ZSet[M cmp.Ordered] struct {
+	dict map[M]float64
+	list *SkipList[zkey[M], struct{}]
+}
+
+// NewZSet 创建有序集合
+func NewZSet[M cmp.Ordered]() *ZSet[M] {
+	return &ZSet[M]{
+		dict: make(map[M]float64),
+		list: New[zkey[M], struct{}](compareZKey[M]),
+	}
+}
+
+// ZCard 成员个数
+func (z *ZSet[M]) ZCard() int {
+	return len(z.dict)
+}
+
+// ZAdd 添加成员或更新成员分数，返回成员是否被添加或分数是否被修改
+func (z *ZSet[M]) ZAdd(score float64, member M, flags ZAddFlag) (changed bool, err error) {
+	nx, xx := flags&ZAddNX != 0, flags&ZAddXX != 0
+	gt, lt := flags&ZAddGT != 0, flags&ZAddLT != 0
+	if nx && xx || gt && lt || nx && (gt || lt) {
+		return false, ErrZAddFlags
+	}
+	if math.IsNaN(score) {
+		return false, ErrZScoreNaN
+	}
+
+	cur, exist := z.dict[member]
+	if !exist {
+		if xx {
+			return false, nil
+		}
+		z.dict[member] = score
+		z.list.Insert(zkey[M]{score: score, member: member}, struct{}{})
+		return true, nil
+	}
+
+	if nx || gt && score <= cur || lt && score >= cur || score == cur {
+		return false, nil
+	}
+	z.update(member, cur, score)
+	return true, nil
+}
+
+// ZIncrBy 成员分数增加incr，成员不存在时以0为初始分数，返回新的分数
+func (z *ZSet[M]) ZIncrBy(incr float64, member M) (float64, error) {
+	cur, exist := z.dict[member]
+	score := cur + incr
+	if math.IsNaN(score) {
+		return cur, ErrZScoreNaN
+	}
+	if !exist {
+		z.dict[member] = score
+		z.list.Insert(zkey[M]{score: score, member: member}, struct{}{})
+		return score, nil
+	}
+	if score != cur {
+		z.update(member, cur, score)
+	}
+	return score, nil
+}
+
+// ZRem 删除成员，返回删除的个数
+func (z *ZSet[M]) ZRem(members ...M) int {
+	var cnt int
+	for _, member := range members {
+		score, exist := z.dict[member]
+		if !exist {
+			continue
+		}
+		delete(z.dict, member)
+		z.list.Delete(zkey[M]{score: score, member: member})
+		cnt++
+	}
+	return cnt
+}
+
+// ZScore 获得成员分数
+func (z *ZSet[M]) ZScore(member M) (float64, bool) {
+	score, exist := z.dict[member]
+	return score, exist
+}
+
+// ZRank 成员按分数升序的排名，从0开始
+func (z *ZSet[M]) ZRank(member M) (int, bool) {
+	score, exist := z.dict[member]
+	if !exist {
+		return 0, false
+	}
+	return z.list.Rank(zkey[M]{score: score, member: member})
+}
+
+// ZRevRank 成员按分数降序的排名，从0开始
+func (z *ZSet[M]) ZRevRank(member M) (int, bool) {
+	score, exist := z.dict[member]
+	if !exist {
+		return 0, false
+	}
+	return z.list.RevRank(zkey[M]{score: score, member: member})
+}
+
+// ZRange 返回升序排名在 [start, stop] 内的成员，负数表示从末尾开始计数
+func (z *ZSet[M]) ZRange(start, stop int) []ZMember[M] {
+	return toZMembers(z.list.RangeByRank(start, stop))
+}
+
+// ZRevRange 返回降序排名在 [start, stop] 内的成员，负数表示从末尾开始计数
+func (z *ZSet[M]) ZRevRange(start, stop int) []ZMember[M] {
+	n := z.ZCard()
+	if start < 0 {
+		start += n
+	}
+	if stop < 0 {
+		stop += n
+	}
+	if start < 0 {
+		start = 0
+	}
+	if stop >= n {
+		stop = n - 1
+	}
+	if start > stop {
+		return nil
+	}
+	ret := z.ZRange(n-1-stop, n-1-start)
+	for i, j := 0, len(ret)-1; i < j; i, j = i+1, j-1 {
+		ret[i], ret[j] = ret[j], ret[i]
+	}
+	return ret
+}
+
+// ZRangeByScore 返回分数在min和max之间的成员，minInclusive/maxInclusive 表示是否包含边界
+func (z *ZSet[M]) ZRangeByScore(min, max float64, minInclusive, maxInclusive bool) []ZMember[M] {
+	var ret []ZMember[M]
+	for node := z.seekScore(min, minInclusive); node != nil && scoreBelow(node.key.score, max, maxInclusive); node = node.levels[0].next {
+		ret = append(ret, ZMember[M]{Member: node.key.member, Score: node.key.score})
+	}
+	return ret
+}
+
+// ZRangeByLex 返回成员在min和max之间的成员，minInclusive/maxInclusive 表示是否包含边界
+// 与redis一致，只有所有成员分数相同时结果才有意义
+func (z *ZSet[M]) ZRangeByLex(min, max M, minInclusive, maxInclusive bool) []ZMember[M] {
+	first, ok := z.list.GetMin()
+	if !ok {
+		return nil
+	}
+	var ret []ZMember[M]
+	node := z.list.seek(zkey[M]{score: first.key.score, member: min})
+	if node != nil && !minInclusive && node.key.member == min {
+		node = node.levels[0].next
+	}
+	for ; node != nil; node = node.levels[0].next {
+		c := cmp.Compare(node.key.member, max)
+		if c > 0 || c == 0 && !maxInclusive {
+			break
+		}
+		ret = append(ret, ZMember[M]{Member: node.key.member, Score: node.key.score})
+	}
+	return ret
+}
+
+// ZCount 分数在min和max之间的成员个数
+func (z *ZSet[M]) ZCount(min, max float64, minInclusive, maxInclusive bool) int {
+	lo := z.rankOf(z.seekScore(min, minInclusive))
+	hi := z.rankOf(z.seekScore(max, !maxInclusive))
+	if hi < lo {
+		return 0
+	}
+	return hi - lo
+}
+
+// ZRemRangeByScore 删除分数在min和max之间的成员，返回删除的个数
+func (z *ZSet[M]) ZRemRangeByScore(min, max float64, minInclusive, maxInclusive bool) int {
+	members := z.ZRangeByScore(min, max, minInclusive, maxInclusive)
+	if len(members) == 0 {
+		return 0
+	}
+	for _, m := range members {
+		delete(z.dict, m.Member)
+	}
+	first := zkey[M]{score: members[0].Score, member: members[0].Member}
+	last := members[len(members)-1]
+	return z.list.DeleteRange(first, zkey[M]{score: last.Score, member: last.Member, bound: 1})
+}
+
+// ZRemRangeByRank 删除升序排名在 [start, stop] 内的成员，返回删除的个数
+func (z *ZSet[M]) ZRemRangeByRank(start, stop int) int {
+	for _, m := range z.ZRange(start, stop) {
+		delete(z.dict, m.Member)
+	}
+	return z.list.DeleteRangeByRank(start, stop)
+}
+
+// update 修改成员分数
+func (z *ZSet[M]) update(member M, oldScore, newScore float64) {
+	z.list.Delete(zkey[M]{score: oldScore, member: member})
+	z.list.Insert(zkey[M]{score: newScore, member: member}, struct{}{})
+	z.dict[member] = newScore
+}
+
+// seekScore 返回第一个分数大于等于（inclusive）或大于score的结点
+func (z *ZSet[M]) seekScore(score float64, inclusive bool) *Node[zkey[M], struct{}] {
+	bound := int8(1)
+	if inclusive {
+		bound = -1
+	}
+	return z.list.seek(zkey[M]{score: score, bound: bound})
+}
+
+// rankOf 返回结点的排名，结点为nil时返回成员个数
+func (z *ZSet[M]) rankOf(node *Node[zkey[M], struct{}]) int {
+	if node == nil {
+		return z.ZCard()
+	}
+	rank, _ := z.list.Rank(node.key)
+	return rank
+}
+
+func scoreBelow(score, max float64, inclusive bool) bool {
+	if inclusive {
+		return score <= max
+	}
+	return score < max
+}
+
+func toZMembers[M cmp.Ordered](nodes []*Node[zkey[M], struct{}]) []ZMember[M] {
+	if len(nodes) == 0 {
+		return nil
+	}
+	ret := make([]ZMember[M], 0, len(nodes))
+	for _, node := range nodes {
+		ret = append(ret, ZMember[M]{Member: node.key.member, Score: node.key.score})
+	}
+	return ret
+}
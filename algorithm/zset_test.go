@@ -0,0 +1,137 @@
+package algorithm
+
+import (
+	"testing"
+)
+
+func zmembers(ms []ZMember[string]) []string {
+	var ret []string
+	for _, m := range ms {
+		ret = append(ret, m.Member)
+	}
+	return ret
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestZSetAdd(t *testing.T) {
+	z := NewZSet[string]()
+	if changed, err := z.ZAdd(10, "a", 0); !changed || err != nil {
+		t.Fatalf("add a = %v, %v", changed, err)
+	}
+	if changed, _ := z.ZAdd(10, "a", 0); changed {
+		t.Fatal("same score reported as changed")
+	}
+	if changed, _ := z.ZAdd(20, "a", ZAddNX); changed {
+		t.Fatal("NX updated existing member")
+	}
+	if changed, _ := z.ZAdd(20, "b", ZAddXX); changed || z.ZCard() != 1 {
+		t.Fatal("XX added new member")
+	}
+	if changed, _ := z.ZAdd(5, "a", ZAddGT); changed {
+		t.Fatal("GT lowered score")
+	}
+	if changed, _ := z.ZAdd(15, "a", ZAddGT|ZAddXX); !changed {
+		t.Fatal("GT did not raise score")
+	}
+	if changed, _ := z.ZAdd(20, "a", ZAddLT); changed {
+		t.Fatal("LT raised score")
+	}
+	if score, _ := z.ZScore("a"); score != 15 {
+		t.Fatalf("score = %v, want 15", score)
+	}
+	if _, err := z.ZAdd(1, "a", ZAddNX|ZAddGT); err != ErrZAddFlags {
+		t.Fatalf("err = %v", err)
+	}
+	if _, err := z.ZAdd(1, "a", ZAddNX|ZAddXX); err != ErrZAddFlags {
+		t.Fatalf("err = %v", err)
+	}
+
+	if score, _ := z.ZIncrBy(5, "a"); score != 20 {
+		t.Fatalf("incr = %v, want 20", score)
+	}
+	if score, _ := z.ZIncrBy(3, "c"); score != 3 {
+		t.Fatalf("incr new = %v, want 3", score)
+	}
+	if rank, _ := z.ZRank("a"); rank != 1 {
+		t.Fatalf("rank(a) = %d, want 1", rank)
+	}
+	if z.ZRem("a", "x") != 1 || z.ZCard() != 1 {
+		t.Fatal("rem")
+	}
+	if _, ok := z.ZRank("a"); ok {
+		t.Fatal("rank of removed member")
+	}
+}
+
+func TestZSetRange(t *testing.T) {
+	z := NewZSet[string]()
+	// 分数相同按成员排序
+	for _, m := range []ZMember[string]{{"e", 3}, {"a", 1}, {"c", 2}, {"b", 2}, {"d", 2}, {"f", 4}} {
+		z.ZAdd(m.Score, m.Member, 0)
+	}
+
+	if got := zmembers(z.ZRange(0, -1)); !equalStrings(got, []string{"a", "b", "c", "d", "e", "f"}) {
+		t.Fatalf("range = %v", got)
+	}
+	if got := zmembers(z.ZRevRange(0, 1)); !equalStrings(got, []string{"f", "e"}) {
+		t.Fatalf("revrange = %v", got)
+	}
+	if got := zmembers(z.ZRevRange(4, 15)); !equalStrings(got, []string{"b", "a"}) {
+		t.Fatalf("revrange = %v", got)
+	}
+	if rank, _ := z.ZRevRank("c"); rank != 3 {
+		t.Fatalf("revrank(c) = %d, want 3", rank)
+	}
+	if got := zmembers(z.ZRangeByScore(2, 3, true, false)); !equalStrings(got, []string{"b", "c", "d"}) {
+		t.Fatalf("rangebyscore = %v", got)
+	}
+	if got := zmembers(z.ZRangeByScore(2, 4, false, true)); !equalStrings(got, []string{"e", "f"}) {
+		t.Fatalf("rangebyscore = %v", got)
+	}
+	if n := z.ZCount(2, 3, true, true); n != 4 {
+		t.Fatalf("count = %d, want 4", n)
+	}
+	if n := z.ZCount(2, 3, false, false); n != 0 {
+		t.Fatalf("count = %d, want 0", n)
+	}
+	if n := z.ZCount(0, 100, true, true); n != 6 {
+		t.Fatalf("count = %d, want 6", n)
+	}
+
+	if n := z.ZRemRangeByScore(2, 2, true, true); n != 3 {
+		t.Fatalf("remrangebyscore = %d, want 3", n)
+	}
+	if n := z.ZRemRangeByRank(-1, -1); n != 1 {
+		t.Fatalf("remrangebyrank = %d, want 1", n)
+	}
+	if got := zmembers(z.ZRange(0, -1)); !equalStrings(got, []string{"a", "e"}) {
+		t.Fatalf("range = %v", got)
+	}
+}
+
+func TestZSetRangeByLex(t *testing.T) {
+	z := NewZSet[string]()
+	for _, m := range []string{"g", "b", "a", "d", "c", "f", "e"} {
+		z.ZAdd(0, m, 0)
+	}
+	if got := zmembers(z.ZRangeByLex("b", "e", true, false)); !equalStrings(got, []string{"b", "c", "d"}) {
+		t.Fatalf("rangebylex = %v", got)
+	}
+	if got := zmembers(z.ZRangeByLex("b", "e", false, true)); !equalStrings(got, []string{"c", "d", "e"}) {
+		t.Fatalf("rangebylex = %v", got)
+	}
+	if got := zmembers(z.ZRangeByLex("", "b", true, true)); !equalStrings(got, []string{"a", "b"}) {
+		t.Fatalf("rangebylex = %v", got)
+	}
+}
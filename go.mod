@@ -0,0 +1,3 @@
+module github.com/ipsozzZ/gtool
+
+go 1.24
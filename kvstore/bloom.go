@@ -0,0 +1,58 @@
+package kvstore
+
+import "hash/fnv"
+
+// bloom 布隆过滤器，使用双重哈希生成k个位置
+// 最后一个字节记录哈希函数个数k，读取时不依赖写入时的配置
+type bloom []byte
+
+// newBloom 为n个key创建过滤器，每个key占用bitsPerKey位
+// 哈希函数个数取最优值 bitsPerKey·ln2
+func newBloom(n, bitsPerKey int) bloom {
+	bits := n * bitsPerKey
+	if bits < 64 {
+		bits = 64
+	}
+	k := int(float64(bitsPerKey)*0.69 + 0.5)
+	k = min(max(k, 1), 30)
+	b := make(bloom, (bits+7)/8+1)
+	b[len(b)-1] = byte(k)
+	return b
+}
+
+func bloomHash(key []byte) (uint32, uint32) {
+	h := fnv.New64a()
+	h.Write(key)
+	sum := h.Sum64()
+	return uint32(sum), uint32(sum>>32) | 1
+}
+
+// hashes 哈希函数个数
+func (b bloom) hashes() uint32 {
+	return uint32(b[len(b)-1])
+}
+
+// add 添加由 bloomHash 计算出的key
+func (b bloom) add(h1, h2 uint32) {
+	m := uint32(len(b)-1) * 8
+	for i := uint32(0); i < b.hashes(); i++ {
+		pos := (h1 + i*h2) % m
+		b[pos/8] |= 1 << (pos % 8)
+	}
+}
+
+// mayContain key可能存在时返回true，返回false时key一定不存在
+func (b bloom) mayContain(key []byte) bool {
+	if len(b) < 2 {
+		return true
+	}
+	h1, h2 := bloomHash(key)
+	m := uint32(len(b)-1) * 8
+	for i := uint32(0); i < b.hashes(); i++ {
+		pos := (h1 + i*h2) % m
+		if b[pos/8]&(1<<(pos%8)) == 0 {
+			return false
+		}
+	}
+	return true
+}
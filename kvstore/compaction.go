@@ -0,0 +1,80 @@
+package kvstore
+
+import "os"
+
+// maybeCompact 表文件个数达到阈值时通知后台合并，调用方持有写锁
+func (db *DB) maybeCompact() {
+	if len(db.tables) < db.opts.CompactionThreshold || db.compacting {
+		return
+	}
+	select {
+	case db.compactCh <- struct{}{}:
+	default:
+	}
+}
+
+// compactLoop 后台合并的goroutine
+func (db *DB) compactLoop() {
+	defer db.wg.Done()
+	for {
+		select {
+		case <-db.done:
+			return
+		case <-db.compactCh:
+			err := db.compact()
+			db.mu.Lock()
+			db.compactErr = err
+			db.mu.Unlock()
+		}
+	}
+}
+
+// compact 把当前所有的表文件合并为一个
+// 合并的是最旧的一段表文件，合并期间新写入的表文件排在结果之后，
+// 参与合并的表文件包含了最旧的数据，所以可以丢弃删除标记
+func (db *DB) compact() error {
+	db.mu.Lock()
+	if db.closed || len(db.tables) < db.opts.CompactionThreshold {
+		db.mu.Unlock()
+		return nil
+	}
+	db.compacting = true
+	inputs := append([]*table(nil), db.tables...)
+	num := db.allocNum()
+	db.mu.Unlock()
+
+	// 表文件不可修改，合并时不需要持有锁
+	srcs := make([]iterator, 0, len(inputs))
+	for i := len(inputs) - 1; i >= 0; i-- {
+		srcs = append(srcs, inputs[i].seek(nil))
+	}
+	out, err := db.writeTableNum(newMergeIter(srcs), num, true)
+
+	db.mu.Lock()
+	db.compacting = false
+	if err != nil {
+		db.mu.Unlock()
+		return err
+	}
+	db.tables = append([]*table{out}, db.tables[len(inputs):]...)
+	if err = db.writeManifest(); err != nil {
+		// 清单未更新，恢复原来的表文件列表
+		db.tables = append(inputs, db.tables[1:]...)
+		db.mu.Unlock()
+		out.close()
+		os.Remove(db.tablePath(out.num))
+		return err
+	}
+	db.maybeCompact()
+	db.mu.Unlock()
+
+	for _, t := range inputs {
+		t.close()
+		os.Remove(db.tablePath(t.num))
+	}
+	select {
+	case db.compacted <- struct{}{}:
+	default:
+	}
+	return nil
+}
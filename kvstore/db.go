@@ -0,0 +1,532 @@
+// Package kvstore 以跳表为内存表的嵌入式有序键值存储（LSM）
+package kvstore
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/ipsozzZ/gtool/algorithm"
+)
+
+var (
+	// ErrNotFound key不存在
+	ErrNotFound = errors.New("kvstore: not found")
+	// ErrClosed 数据库已关闭
+	ErrClosed = errors.New("kvstore: closed")
+	// ErrEmptyKey key为空
+	ErrEmptyKey = errors.New("kvstore: empty key")
+	// ErrCorrupt 数据文件损坏
+	ErrCorrupt = errors.New("kvstore: corrupted data")
+)
+
+const (
+	manifestName = "MANIFEST"
+	memDirPrefix = "mem-"
+	tableSuffix  = ".sst"
+)
+
+// Options 数据库配置，零值字段使用默认值
+type Options struct {
+	// MemtableSize 内存表的数据量超过该值时转为只读内存表并在后台写入表文件，默认4MB
+	MemtableSize int
+	// BlockSize 表文件数据块的大小，默认4KB
+	BlockSize int
+	// BloomBitsPerKey 布隆过滤器每个key占用的位数，默认10
+	BloomBitsPerKey int
+	// CompactionThreshold 表文件个数达到该值时在后台合并，默认4
+	CompactionThreshold int
+	// Sync 预写日志的刷盘策略，默认每次写入都fsync
+	Sync algorithm.SyncPolicy
+}
+
+func (o *Options) fill() {
+	if o.MemtableSize <= 0 {
+		o.MemtableSize = 4 << 20
+	}
+	if o.BlockSize <= 0 {
+		o.BlockSize = 4 << 10
+	}
+	if o.BloomBitsPerKey <= 0 {
+		o.BloomBitsPerKey = 10
+	}
+	if o.CompactionThreshold < 2 {
+		o.CompactionThreshold = 4
+	}
+}
+
+// DB 嵌入式有序键值存储，可以并发使用
+// 写入先记录到内存表的预写日志，内存表写满后转为只读内存表，在后台写入表文件，
+// 写入期间新的写操作进入新的内存表，不会被阻塞；
+// 表文件个数达到阈值后在后台合并为一个，合并时丢弃被覆盖的记录和删除标记
+type DB struct {
+	mu        sync.RWMutex
+	dir       string
+	opts      Options
+	mem       *memtable
+	imm       *memtable // 等待写入表文件的只读内存表
+	tables    []*table  // 从旧到新排列
+	nextNum   uint64
+	closed    bool
+	flushing  bool
+	flushDone *sync.Cond // flushing 变为false时通知
+
+	compacting bool
+	compactCh  chan struct{}
+	compacted  chan struct{} // 每次合并完成后通知，用于测试
+	done       chan struct{}
+	wg         sync.WaitGroup
+	flushErr   error // 后台写入表文件的错误，之后写入成功时清除
+	compactErr error // 后台合并的错误，之后合并成功时清除
+}
+
+// Open 打开或创建dir中的数据库
+func Open(dir string, opts Options) (*DB, error) {
+	opts.fill()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	db := &DB{
+		dir:       dir,
+		opts:      opts,
+		nextNum:   1,
+		compactCh: make(chan struct{}, 1),
+		compacted: make(chan struct{}, 1),
+		done:      make(chan struct{}),
+	}
+	db.flushDone = sync.NewCond(&db.mu)
+	if err := db.recover(); err != nil {
+		db.closeFiles()
+		return nil, err
+	}
+	db.wg.Add(1)
+	go db.compactLoop()
+	db.maybeCompact()
+	return db, nil
+}
+
+// Put 写入key
+func (db *DB) Put(key, value []byte) error {
+	return db.write(entry{key: key, value: value})
+}
+
+// Delete 删除key，key不存在时不返回错误
+func (db *DB) Delete(key []byte) error {
+	return db.write(entry{key: key, deleted: true})
+}
+
+// write 写入一条记录，返回错误时记录没有写入
+// 内存表写满只会触发后台写入表文件，后台的错误不影响写入，通过 BackgroundError 获取
+func (db *DB) write(e entry) error {
+	if len(e.key) == 0 {
+		return ErrEmptyKey
+	}
+	e.key = append([]byte(nil), e.key...)
+	e.value = append([]byte(nil), e.value...)
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	if db.closed {
+		return ErrClosed
+	}
+	if err := db.mem.put(e); err != nil {
+		return err
+	}
+	db.maybeFlush()
+	return nil
+}
+
+// Get 查找key，不存在时返回 ErrNotFound
+func (db *DB) Get(key []byte) ([]byte, error) {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	if db.closed {
+		return nil, ErrClosed
+	}
+	e, found := db.mem.get(key)
+	if !found && db.imm != nil {
+		e, found = db.imm.get(key)
+	}
+	for i := len(db.tables) - 1; !found && i >= 0; i-- {
+		var err error
+		if e, found, err = db.tables[i].get(key); err != nil {
+			return nil, err
+		}
+	}
+	if !found || e.deleted {
+		return nil, ErrNotFound
+	}
+	return append([]byte(nil), e.value...), nil
+}
+
+// Scan 按key升序遍历 [start, end) 内的记录，start 为nil时从头开始，end 为nil时遍历到末尾
+// fn 返回false时停止；遍历期间持有读锁，写入会被阻塞，fn 中不能调用写操作
+// 传给fn的切片只在调用期间有效
+func (db *DB) Scan(start, end []byte, fn func(key, value []byte) bool) error {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	if db.closed {
+		return ErrClosed
+	}
+	srcs := []iterator{db.mem.seek(start)}
+	if db.imm != nil {
+		srcs = append(srcs, db.imm.seek(start))
+	}
+	for i := len(db.tables) - 1; i >= 0; i-- {
+		srcs = append(srcs, db.tables[i].seek(start))
+	}
+	it := newMergeIter(srcs)
+	for it.next() {
+		if end != nil && bytes.Compare(it.cur.key, end) >= 0 {
+			return nil
+		}
+		if !it.cur.deleted && !fn(it.cur.key, it.cur.value) {
+			return nil
+		}
+	}
+	return it.err
+}
+
+// Flush 把内存表写入表文件，等待正在进行的后台写入完成后同步写入
+// 之前写入失败遗留的只读内存表也会一起写入
+func (db *DB) Flush() error {
+	for {
+		db.mu.Lock()
+		for db.flushing {
+			db.flushDone.Wait()
+		}
+		if db.closed {
+			db.mu.Unlock()
+			return ErrClosed
+		}
+		if db.imm == nil {
+			if db.mem.len() == 0 {
+				db.mu.Unlock()
+				return nil
+			}
+			if err := db.rotate(); err != nil {
+				db.mu.Unlock()
+				return err
+			}
+		}
+		db.flushing = true
+		imm, num := db.imm, db.allocNum()
+		db.mu.Unlock()
+		if err := db.flushImm(imm, num); err != nil {
+			return err
+		}
+	}
+}
+
+// Close 等待后台合并结束并关闭文件，内存表中的数据保留在预写日志中
+func (db *DB) Close() error {
+	db.mu.Lock()
+	if db.closed {
+		db.mu.Unlock()
+		return ErrClosed
+	}
+	db.closed = true
+	for db.flushing {
+		db.flushDone.Wait()
+	}
+	db.mu.Unlock()
+
+	close(db.done)
+	db.wg.Wait()
+	err := db.closeFiles()
+	if err == nil {
+		err = db.backgroundError()
+	}
+	return err
+}
+
+// BackgroundError 返回后台写入表文件或合并遇到的错误，没有错误时返回nil
+// 写入表文件的错误保留到之后某次写入成功（后台重试或 Flush），合并的错误保留到之后某次合并成功；
+// 后台错误不影响 Put/Delete，没有写入表文件的数据仍在内存表和预写日志中
+func (db *DB) BackgroundError() error {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+	return db.backgroundError()
+}
+
+func (db *DB) backgroundError() error {
+	if db.flushErr != nil {
+		return db.flushErr
+	}
+	return db.compactErr
+}
+
+func (db *DB) closeFiles() error {
+	var err error
+	for _, mem := range []*memtable{db.mem, db.imm} {
+		if mem == nil {
+			continue
+		}
+		if cerr := mem.close(); err == nil {
+			err = cerr
+		}
+	}
+	for _, t := range db.tables {
+		if cerr := t.close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// maybeFlush 内存表写满时转为只读内存表，并在后台写入表文件，调用方持有写锁
+// 上一次写入失败时重试写入遗留的只读内存表
+func (db *DB) maybeFlush() {
+	if db.flushing || db.closed {
+		return
+	}
+	if db.imm == nil {
+		if db.mem.size < db.opts.MemtableSize {
+			return
+		}
+		if err := db.rotate(); err != nil {
+			db.flushErr = err
+			return
+		}
+	}
+	db.flushing = true
+	imm, num := db.imm, db.allocNum()
+	db.wg.Add(1)
+	go func() {
+		defer db.wg.Done()
+		db.flushImm(imm, num)
+	}()
+}
+
+// rotate 把内存表转为只读内存表并切换到新的内存表，调用方持有写锁且没有只读内存表
+func (db *DB) rotate() error {
+	num := db.allocNum()
+	mem, err := openMemtable(db.memDir(num), num, db.opts.Sync)
+	if err != nil {
+		return err
+	}
+	db.imm, db.mem = db.mem, mem
+	return nil
+}
+
+// flushImm 把只读内存表写入编号为num的表文件，写入表文件时不持有锁
+// 调用方已将 flushing 设为true；失败时保留只读内存表并记录错误，之后重试，成功后清除错误
+func (db *DB) flushImm(imm *memtable, num uint64) error {
+	// 只读内存表不会再被修改，可以与读操作并发遍历
+	t, err := db.writeTableNum(imm.seek(nil), num, false)
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.flushing = false
+	db.flushDone.Broadcast()
+	if err == nil {
+		err = db.addTable(t)
+	}
+	db.flushErr = err
+	if err != nil {
+		return err
+	}
+	db.imm = nil
+	db.dropMem(imm)
+	db.maybeFlush()
+	return nil
+}
+
+// persistMem 把内存表写入表文件并记录到清单
+func (db *DB) persistMem(mem *memtable) error {
+	if mem.len() == 0 {
+		return nil
+	}
+	t, err := db.writeTable(mem.seek(nil))
+	if err != nil {
+		return err
+	}
+	return db.addTable(t)
+}
+
+// addTable 把新写入的表文件记录到清单，失败时删除表文件，调用方持有写锁
+func (db *DB) addTable(t *table) error {
+	db.tables = append(db.tables, t)
+	if err := db.writeManifest(); err != nil {
+		db.tables = db.tables[:len(db.tables)-1]
+		t.close()
+		os.Remove(db.tablePath(t.num))
+		return err
+	}
+	return nil
+}
+
+// dropMem 关闭已写入表文件的内存表并删除它的预写日志
+func (db *DB) dropMem(mem *memtable) {
+	mem.close()
+	os.RemoveAll(db.memDir(mem.num))
+	db.maybeCompact()
+}
+
+// writeTable 把迭代器中的记录写入新的表文件，dropDeleted 为true时丢弃删除标记
+func (db *DB) writeTable(it iterator) (*table, error) {
+	return db.writeTableNum(it, db.allocNum(), false)
+}
+
+func (db *DB) writeTableNum(it iterator, num uint64, dropDeleted bool) (*table, error) {
+	path := db.tablePath(num)
+	tw, err := newTableWriter(path, db.opts.BlockSize, db.opts.BloomBitsPerKey)
+	if err != nil {
+		return nil, err
+	}
+	for it.next() {
+		if dropDeleted && it.current().deleted {
+			continue
+		}
+		if err = tw.add(it.current()); err != nil {
+			tw.abort()
+			return nil, err
+		}
+	}
+	if err = it.error(); err != nil {
+		tw.abort()
+		return nil, err
+	}
+	if err = tw.finish(); err != nil {
+		os.Remove(path)
+		return nil, err
+	}
+	return openTable(path, num)
+}
+
+// allocNum 分配文件编号，调用方持有写锁
+func (db *DB) allocNum() uint64 {
+	num := db.nextNum
+	db.nextNum++
+	return num
+}
+
+// writeManifest 原子地写入当前的表文件列表，调用方持有写锁
+// 格式：第一行为下一个文件编号，之后每行一个表文件编号，从旧到新排列
+func (db *DB) writeManifest() error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d\n", db.nextNum)
+	for _, t := range db.tables {
+		fmt.Fprintf(&b, "%d\n", t.num)
+	}
+	path := filepath.Join(db.dir, manifestName)
+	f, err := os.Create(path + ".tmp")
+	if err != nil {
+		return err
+	}
+	_, err = f.WriteString(b.String())
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(path+".tmp", path)
+	}
+	if err == nil {
+		err = syncDir(db.dir)
+	}
+	return err
+}
+
+// recover 加载清单中的表文件，把遗留的预写日志写入表文件，删除不在清单中的文件
+func (db *DB) recover() error {
+	var nums []uint64
+	f, err := os.Open(filepath.Join(db.dir, manifestName))
+	switch {
+	case err == nil:
+		sc := bufio.NewScanner(f)
+		for first := true; sc.Scan(); first = false {
+			var num uint64
+			if _, err = fmt.Sscanf(sc.Text(), "%d", &num); err != nil {
+				f.Close()
+				return fmt.Errorf("%w: manifest: %v", ErrCorrupt, err)
+			}
+			if first {
+				db.nextNum = num
+			} else {
+				nums = append(nums, num)
+			}
+		}
+		f.Close()
+		if err = sc.Err(); err != nil {
+			return err
+		}
+	case !os.IsNotExist(err):
+		return err
+	}
+
+	live := make(map[string]bool)
+	for _, num := range nums {
+		t, err := openTable(db.tablePath(num), num)
+		if err != nil {
+			return fmt.Errorf("open table %d: %w", num, err)
+		}
+		db.tables = append(db.tables, t)
+		live[filepath.Base(db.tablePath(num))] = true
+	}
+
+	entries, err := os.ReadDir(db.dir)
+	if err != nil {
+		return err
+	}
+	var memNums []uint64
+	for _, e := range entries {
+		name := e.Name()
+		var num uint64
+		switch {
+		case e.IsDir() && strings.HasPrefix(name, memDirPrefix):
+			if _, err := fmt.Sscanf(name[len(memDirPrefix):], "%d", &num); err == nil {
+				memNums = append(memNums, num)
+			}
+		case strings.HasSuffix(name, tableSuffix) && !live[name]:
+			// 合并或写入过程中中断留下的表文件
+			os.Remove(filepath.Join(db.dir, name))
+		}
+	}
+
+	// 遗留的内存表按编号从旧到新写入表文件
+	slices.Sort(memNums)
+	for _, num := range memNums {
+		if num >= db.nextNum {
+			db.nextNum = num + 1
+		}
+		mem, err := openMemtable(db.memDir(num), num, db.opts.Sync)
+		if err != nil {
+			return err
+		}
+		if err = db.persistMem(mem); err != nil {
+			mem.close()
+			return err
+		}
+		db.dropMem(mem)
+	}
+	num := db.allocNum()
+	db.mem, err = openMemtable(db.memDir(num), num, db.opts.Sync)
+	return err
+}
+
+func (db *DB) memDir(num uint64) string {
+	return filepath.Join(db.dir, fmt.Sprintf("%s%016d", memDirPrefix, num))
+}
+
+func (db *DB) tablePath(num uint64) string {
+	return filepath.Join(db.dir, fmt.Sprintf("%016d%s", num, tableSuffix))
+}
+
+// syncDir fsync目录，保证重命名和新建的文件持久化
+func syncDir(dir string) error {
+	f, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return f.Sync()
+}
@@ -0,0 +1,299 @@
+package kvstore
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ipsozzZ/gtool/algorithm"
+)
+
+func mustGet(t *testing.T, db *DB, key string) string {
+	t.Helper()
+	v, err := db.Get([]byte(key))
+	if err != nil {
+		t.Fatalf("get %s: %v", key, err)
+	}
+	return string(v)
+}
+
+func scanKeys(t *testing.T, db *DB, start, end []byte) []string {
+	t.Helper()
+	var keys []string
+	if err := db.Scan(start, end, func(k, v []byte) bool {
+		keys = append(keys, string(k))
+		return true
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return keys
+}
+
+// waitFlush 等待后台写入表文件完成
+func waitFlush(db *DB) {
+	db.mu.Lock()
+	for db.flushing {
+		db.flushDone.Wait()
+	}
+	db.mu.Unlock()
+}
+
+func countTables(t *testing.T, dir string) int {
+	t.Helper()
+	matches, _ := filepath.Glob(filepath.Join(dir, "*"+tableSuffix))
+	return len(matches)
+}
+
+func TestDBBasic(t *testing.T) {
+	db, err := Open(t.TempDir(), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	db.Put([]byte("b"), []byte("2"))
+	db.Put([]byte("a"), []byte("1"))
+	db.Put([]byte("c"), []byte("3"))
+	db.Put([]byte("a"), []byte("one"))
+	db.Delete([]byte("b"))
+	if v := mustGet(t, db, "a"); v != "one" {
+		t.Fatalf("a = %s", v)
+	}
+	if _, err := db.Get([]byte("b")); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("get deleted = %v", err)
+	}
+	if err := db.Put(nil, []byte("x")); !errors.Is(err, ErrEmptyKey) {
+		t.Fatalf("empty key = %v", err)
+	}
+	if keys := scanKeys(t, db, nil, nil); strings.Join(keys, ",") != "a,c" {
+		t.Fatalf("scan = %v", keys)
+	}
+}
+
+func TestDBFlushAndRecover(t *testing.T) {
+	dir := t.TempDir()
+	opts := Options{MemtableSize: 512, BlockSize: 64, CompactionThreshold: 1000}
+	db, err := Open(dir, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 200; i++ {
+		db.Put([]byte(fmt.Sprintf("key%03d", i)), []byte(fmt.Sprintf("v%d", i)))
+	}
+	for i := 0; i < 200; i += 2 {
+		db.Delete([]byte(fmt.Sprintf("key%03d", i)))
+	}
+	db.Put([]byte("key001"), []byte("new"))
+	waitFlush(db)
+	if countTables(t, dir) < 2 {
+		t.Fatalf("memtable not flushed, tables = %d", countTables(t, dir))
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err = Open(dir, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if v := mustGet(t, db, "key001"); v != "new" {
+		t.Fatalf("key001 = %s", v)
+	}
+	if v := mustGet(t, db, "key199"); v != "v199" {
+		t.Fatalf("key199 = %s", v)
+	}
+	if _, err := db.Get([]byte("key100")); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("deleted key = %v", err)
+	}
+	keys := scanKeys(t, db, []byte("key010"), []byte("key020"))
+	if strings.Join(keys, ",") != "key011,key013,key015,key017,key019" {
+		t.Fatalf("scan = %v", keys)
+	}
+	if n := len(scanKeys(t, db, nil, nil)); n != 100 {
+		t.Fatalf("scan all = %d, want 100", n)
+	}
+}
+
+func TestDBCompaction(t *testing.T) {
+	dir := t.TempDir()
+	db, err := Open(dir, Options{MemtableSize: 256, BlockSize: 64, CompactionThreshold: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for round := 0; round < 5; round++ {
+		for i := 0; i < 50; i++ {
+			db.Put([]byte(fmt.Sprintf("k%02d", i)), []byte(fmt.Sprintf("r%d", round)))
+		}
+		db.Flush()
+	}
+	for i := 0; i < 50; i += 5 {
+		db.Delete([]byte(fmt.Sprintf("k%02d", i)))
+	}
+	db.Flush()
+
+	// 等待后台合并完成
+	for countTables(t, dir) >= 3 {
+		select {
+		case <-db.compacted:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("tables after compaction = %d", countTables(t, dir))
+		}
+	}
+	if v := mustGet(t, db, "k01"); v != "r4" {
+		t.Fatalf("k01 = %s, want r4", v)
+	}
+	if n := len(scanKeys(t, db, nil, nil)); n != 40 {
+		t.Fatalf("scan = %d keys, want 40", n)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err = Open(dir, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err := db.Get([]byte("k05")); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("deleted key after compaction = %v", err)
+	}
+	if v := mustGet(t, db, "k49"); v != "r4" {
+		t.Fatalf("k49 = %s", v)
+	}
+}
+
+func TestDBFlushError(t *testing.T) {
+	dir := t.TempDir()
+	db, err := Open(dir, Options{MemtableSize: 64, CompactionThreshold: 1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	// 下一个表文件的位置被目录占用，后台写入表文件失败
+	db.mu.Lock()
+	blocked := db.tablePath(db.nextNum + 1)
+	db.mu.Unlock()
+	os.Mkdir(blocked, 0o755)
+
+	written := 0
+	for ; written < 10; written++ {
+		if err := db.Put([]byte(fmt.Sprintf("k%d", written)), []byte("0123456789")); err != nil {
+			// 写满内存表的写入已经写入预写日志，不能返回错误
+			t.Fatalf("put %d = %v", written, err)
+		}
+		waitFlush(db)
+		if db.BackgroundError() != nil {
+			written++
+			break
+		}
+	}
+	if written == 10 {
+		t.Fatal("flush did not fail")
+	}
+	// 后台错误在重试成功前一直保留，不影响之后的写入
+	if db.BackgroundError() == nil {
+		t.Fatal("background error cleared without retry")
+	}
+	if err := db.Put([]byte("x"), []byte("1")); err != nil {
+		t.Fatalf("put after flush error = %v", err)
+	}
+	if v := mustGet(t, db, "x"); v != "1" {
+		t.Fatalf("x = %s", v)
+	}
+	// 这次写入触发重试，重试使用新的表文件编号，成功后错误被清除
+	waitFlush(db)
+	if err := db.BackgroundError(); err != nil {
+		t.Fatalf("background error after retry = %v", err)
+	}
+	if err := db.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < written; i++ {
+		if v := mustGet(t, db, fmt.Sprintf("k%d", i)); v != "0123456789" {
+			t.Fatalf("k%d = %s", i, v)
+		}
+	}
+}
+
+func TestDBCorruptTable(t *testing.T) {
+	dir := t.TempDir()
+	db, _ := Open(dir, Options{CompactionThreshold: 1000})
+	db.Put([]byte("a"), []byte("1"))
+	db.Flush()
+	db.Close()
+
+	matches, _ := filepath.Glob(filepath.Join(dir, "*"+tableSuffix))
+	data, _ := os.ReadFile(matches[0])
+	data[0] ^= 0xff
+	os.WriteFile(matches[0], data, 0o644)
+	db, err := Open(dir, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err := db.Get([]byte("a")); !errors.Is(err, ErrCorrupt) {
+		t.Fatalf("get from corrupted table = %v", err)
+	}
+}
+
+func TestDBConcurrent(t *testing.T) {
+	db, err := Open(t.TempDir(), Options{MemtableSize: 1024, CompactionThreshold: 2, Sync: algorithm.SyncNever})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				key := []byte(fmt.Sprintf("w%d-%03d", w, i))
+				if err := db.Put(key, key); err != nil {
+					t.Error(err)
+					return
+				}
+				if v, err := db.Get(key); err != nil || string(v) != string(key) {
+					t.Errorf("get %s = %s, %v", key, v, err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if n := len(scanKeys(t, db, nil, nil)); n != 800 {
+		t.Fatalf("scan = %d, want 800", n)
+	}
+}
+
+func TestBloom(t *testing.T) {
+	for _, bitsPerKey := range []int{4, 10, 20} {
+		b := newBloom(1000, bitsPerKey)
+		if want := uint32(float64(bitsPerKey)*0.69 + 0.5); b.hashes() != want {
+			t.Fatalf("bits %d: hashes = %d, want %d", bitsPerKey, b.hashes(), want)
+		}
+		for i := 0; i < 1000; i++ {
+			b.add(bloomHash([]byte(fmt.Sprint(i))))
+		}
+		falsePositive := 0
+		for i := 0; i < 1000; i++ {
+			if !b.mayContain([]byte(fmt.Sprint(i))) {
+				t.Fatalf("bits %d: missing key %d", bitsPerKey, i)
+			}
+			if b.mayContain([]byte(fmt.Sprint(-i - 1))) {
+				falsePositive++
+			}
+		}
+		// 理论误判率约为 0.6185^bitsPerKey
+		if limit := 1000 * math.Pow(0.6185, float64(bitsPerKey)) * 2; float64(falsePositive) > limit+5 {
+			t.Fatalf("bits %d: false positives = %d, limit %.0f", bitsPerKey, falsePositive, limit)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package kvstore
+
+import (
+	"bytes"
+
+	"github.com/ipsozzZ/gtool/algorithm"
+)
+
+// memValue 内存表中的value，deleted 为true时表示删除标记
+type memValue struct {
+	value   []byte
+	deleted bool
+}
+
+// memValueCodec 内存表value的编解码器，首字节为记录类型
+type memValueCodec struct{}
+
+func (memValueCodec) Encode(v memValue) ([]byte, error) {
+	kind := kindValue
+	if v.deleted {
+		kind = kindTombstone
+	}
+	return append([]byte{kind}, v.value...), nil
+}
+
+func (memValueCodec) Decode(data []byte) (memValue, error) {
+	if len(data) == 0 {
+		return memValue{}, algorithm.ErrCodecData
+	}
+	return memValue{value: append([]byte(nil), data[1:]...), deleted: data[0] == kindTombstone}, nil
+}
+
+// memtable 内存表，用跳表按key排序，写入先记录到预写日志
+type memtable struct {
+	num  uint64
+	list *algorithm.DurableSkipList[[]byte, memValue]
+	size int // 写入的数据量估计
+}
+
+// openMemtable 打开dir中的预写日志恢复内存表
+func openMemtable(dir string, num uint64, sync algorithm.SyncPolicy) (*memtable, error) {
+	l := algorithm.New[[]byte, memValue](bytes.Compare)
+	l.SetCodec(algorithm.BytesCodec{}, memValueCodec{})
+	d, err := algorithm.OpenDurable(l, algorithm.DurableConfig{Dir: dir, Sync: sync})
+	if err != nil {
+		return nil, err
+	}
+	m := &memtable{num: num, list: d}
+	for k, v := range l.All() {
+		m.size += len(k) + len(v.value)
+	}
+	return m, nil
+}
+
+// put 写入一条记录
+func (m *memtable) put(e entry) error {
+	if err := m.list.Insert(e.key, memValue{value: e.value, deleted: e.deleted}); err != nil {
+		return err
+	}
+	m.size += len(e.key) + len(e.value)
+	return nil
+}
+
+// get 查找key，found 为false表示内存表中没有这个key
+func (m *memtable) get(key []byte) (entry, bool) {
+	node, ok := m.list.List().Find(key, 0)
+	if !ok {
+		return entry{}, false
+	}
+	v := node.GetValue()
+	return entry{key: node.GetKey(), value: v.value, deleted: v.deleted}, true
+}
+
+// len 记录数
+func (m *memtable) len() int {
+	return int(m.list.List().Len())
+}
+
+// seek 返回从第一个不小于start的记录开始的迭代器
+func (m *memtable) seek(start []byte) *memIter {
+	node, _ := m.list.List().GetMin()
+	if start != nil {
+		node, _ = m.list.List().Ceiling(start)
+	}
+	return &memIter{node: node}
+}
+
+func (m *memtable) close() error {
+	return m.list.Close()
+}
+
+// memIter 内存表的顺序迭代器
+type memIter struct {
+	node  *algorithm.Node[[]byte, memValue]
+	cur   entry
+	begun bool
+}
+
+func (it *memIter) next() bool {
+	if it.begun {
+		it.node, _ = it.node.Next()
+	}
+	it.begun = true
+	if it.node == nil {
+		return false
+	}
+	v := it.node.GetValue()
+	it.cur = entry{key: it.node.GetKey(), value: v.value, deleted: v.deleted}
+	return true
+}
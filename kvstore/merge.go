@@ -0,0 +1,64 @@
+package kvstore
+
+import "bytes"
+
+// iterator 按key升序的记录迭代器
+type iterator interface {
+	next() bool
+	current() entry
+	error() error
+}
+
+func (it *tableIter) current() entry { return it.cur }
+func (it *tableIter) error() error   { return it.err }
+func (it *memIter) current() entry   { return it.cur }
+func (it *memIter) error() error     { return nil }
+func (m *mergeIter) current() entry  { return m.cur }
+func (m *mergeIter) error() error    { return m.err }
+
+// mergeIter 合并多个迭代器，同一个key只返回最新的记录
+type mergeIter struct {
+	srcs  []iterator // 从新到旧排列
+	valid []bool
+	cur   entry
+	err   error
+}
+
+func newMergeIter(srcs []iterator) *mergeIter {
+	m := &mergeIter{srcs: srcs, valid: make([]bool, len(srcs))}
+	for i, src := range srcs {
+		m.valid[i] = src.next()
+	}
+	return m
+}
+
+// next 移动到下一个key，没有更多记录或出错时返回false
+func (m *mergeIter) next() bool {
+	if m.err != nil {
+		return false
+	}
+	pick := -1
+	for i, src := range m.srcs {
+		if !m.valid[i] {
+			if err := src.error(); err != nil {
+				m.err = err
+				return false
+			}
+			continue
+		}
+		// key相同时保留较新的来源
+		if pick < 0 || bytes.Compare(src.current().key, m.srcs[pick].current().key) < 0 {
+			pick = i
+		}
+	}
+	if pick < 0 {
+		return false
+	}
+	m.cur = m.srcs[pick].current()
+	for i, src := range m.srcs {
+		if m.valid[i] && bytes.Equal(src.current().key, m.cur.key) {
+			m.valid[i] = src.next()
+		}
+	}
+	return true
+}
@@ -0,0 +1,364 @@
+package kvstore
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"os"
+	"sort"
+)
+
+// 表文件格式：
+//
+//	data block * n
+//	index block: n * [lastKeyLen(uvarint) lastKey offset(uvarint) size(uvarint)]
+//	bloom block: 位数组 hashCount(1)
+//	footer: indexOffset indexSize bloomOffset bloomSize count magic（各8字节，大端）
+//
+// 每个block之后是4字节的crc32（大端），block 的size不包含crc
+// data block 中的记录：keyLen(uvarint) key kind(1) valueLen(uvarint) value
+const (
+	tableMagic      = 0x67746f6f6c6b7631 // "gtoolkv1"
+	tableFooterSize = 48
+
+	kindValue     byte = 0
+	kindTombstone byte = 1
+)
+
+// entry 一条记录，deleted 为true时表示删除标记
+type entry struct {
+	key     []byte
+	value   []byte
+	deleted bool
+}
+
+// indexEntry 数据块的索引
+type indexEntry struct {
+	lastKey []byte
+	offset  int64
+	size    int
+}
+
+// tableWriter 按key升序写入表文件
+type tableWriter struct {
+	f          *os.File
+	w          *bufio.Writer
+	offset     int64
+	block      []byte
+	blockSize  int
+	index      []indexEntry
+	hashes     [][2]uint32 // 用于构建布隆过滤器
+	lastKey    []byte
+	bitsPerKey int
+}
+
+func newTableWriter(path string, blockSize, bitsPerKey int) (*tableWriter, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	return &tableWriter{f: f, w: bufio.NewWriter(f), blockSize: blockSize, bitsPerKey: bitsPerKey}, nil
+}
+
+// add 追加一条记录，调用方保证key严格递增
+func (tw *tableWriter) add(e entry) error {
+	tw.block = binary.AppendUvarint(tw.block, uint64(len(e.key)))
+	tw.block = append(tw.block, e.key...)
+	kind := kindValue
+	if e.deleted {
+		kind = kindTombstone
+	}
+	tw.block = append(tw.block, kind)
+	tw.block = binary.AppendUvarint(tw.block, uint64(len(e.value)))
+	tw.block = append(tw.block, e.value...)
+	tw.lastKey = append(tw.lastKey[:0], e.key...)
+	h1, h2 := bloomHash(e.key)
+	tw.hashes = append(tw.hashes, [2]uint32{h1, h2})
+	if len(tw.block) >= tw.blockSize {
+		return tw.flushBlock()
+	}
+	return nil
+}
+
+// flushBlock 写入当前的数据块并记录索引
+func (tw *tableWriter) flushBlock() error {
+	if len(tw.block) == 0 {
+		return nil
+	}
+	tw.index = append(tw.index, indexEntry{
+		lastKey: append([]byte(nil), tw.lastKey...),
+		offset:  tw.offset,
+		size:    len(tw.block),
+	})
+	err := tw.writeBlock(tw.block)
+	tw.block = tw.block[:0]
+	return err
+}
+
+func (tw *tableWriter) writeBlock(data []byte) error {
+	var sum [4]byte
+	binary.BigEndian.PutUint32(sum[:], crc32.ChecksumIEEE(data))
+	if _, err := tw.w.Write(data); err != nil {
+		return err
+	}
+	if _, err := tw.w.Write(sum[:]); err != nil {
+		return err
+	}
+	tw.offset += int64(len(data)) + 4
+	return nil
+}
+
+// finish 写入索引、布隆过滤器和footer，并fsync关闭文件
+func (tw *tableWriter) finish() error {
+	err := tw.finishBlocks()
+	if err == nil {
+		err = tw.f.Sync()
+	}
+	if cerr := tw.f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+func (tw *tableWriter) finishBlocks() error {
+	if err := tw.flushBlock(); err != nil {
+		return err
+	}
+	var index []byte
+	for _, ie := range tw.index {
+		index = binary.AppendUvarint(index, uint64(len(ie.lastKey)))
+		index = append(index, ie.lastKey...)
+		index = binary.AppendUvarint(index, uint64(ie.offset))
+		index = binary.AppendUvarint(index, uint64(ie.size))
+	}
+	indexOffset := tw.offset
+	if err := tw.writeBlock(index); err != nil {
+		return err
+	}
+
+	filter := newBloom(len(tw.hashes), tw.bitsPerKey)
+	for _, h := range tw.hashes {
+		filter.add(h[0], h[1])
+	}
+	bloomOffset := tw.offset
+	if err := tw.writeBlock(filter); err != nil {
+		return err
+	}
+
+	var footer [tableFooterSize]byte
+	binary.BigEndian.PutUint64(footer[0:], uint64(indexOffset))
+	binary.BigEndian.PutUint64(footer[8:], uint64(len(index)))
+	binary.BigEndian.PutUint64(footer[16:], uint64(bloomOffset))
+	binary.BigEndian.PutUint64(footer[24:], uint64(len(filter)))
+	binary.BigEndian.PutUint64(footer[32:], uint64(len(tw.hashes)))
+	binary.BigEndian.PutUint64(footer[40:], tableMagic)
+	if _, err := tw.w.Write(footer[:]); err != nil {
+		return err
+	}
+	return tw.w.Flush()
+}
+
+// abort 放弃写入并删除文件
+func (tw *tableWriter) abort() {
+	tw.f.Close()
+	os.Remove(tw.f.Name())
+}
+
+// table 只读的表文件，索引和布隆过滤器常驻内存
+type table struct {
+	num    uint64
+	f      *os.File
+	index  []indexEntry
+	filter bloom
+	count  int
+}
+
+// openTable 打开表文件并加载索引和布隆过滤器
+func openTable(path string, num uint64) (*table, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	t := &table{num: num, f: f}
+	if err = t.load(); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return t, nil
+}
+
+func (t *table) load() error {
+	info, err := t.f.Stat()
+	if err != nil {
+		return err
+	}
+	if info.Size() < tableFooterSize {
+		return ErrCorrupt
+	}
+	var footer [tableFooterSize]byte
+	if _, err = t.f.ReadAt(footer[:], info.Size()-tableFooterSize); err != nil {
+		return err
+	}
+	if binary.BigEndian.Uint64(footer[40:]) != tableMagic {
+		return ErrCorrupt
+	}
+	indexOffset := int64(binary.BigEndian.Uint64(footer[0:]))
+	indexSize := int(binary.BigEndian.Uint64(footer[8:]))
+	bloomOffset := int64(binary.BigEndian.Uint64(footer[16:]))
+	bloomSize := int(binary.BigEndian.Uint64(footer[24:]))
+	t.count = int(binary.BigEndian.Uint64(footer[32:]))
+	if indexOffset+int64(indexSize) > info.Size() || bloomOffset+int64(bloomSize) > info.Size() {
+		return ErrCorrupt
+	}
+
+	index, err := t.readBlock(indexOffset, indexSize)
+	if err != nil {
+		return err
+	}
+	for len(index) > 0 {
+		var ie indexEntry
+		var off, size uint64
+		if ie.lastKey, index, err = readBytes(index); err != nil {
+			return err
+		}
+		if off, index, err = readUvarint(index); err != nil {
+			return err
+		}
+		if size, index, err = readUvarint(index); err != nil {
+			return err
+		}
+		ie.offset, ie.size = int64(off), int(size)
+		t.index = append(t.index, ie)
+	}
+
+	filter, err := t.readBlock(bloomOffset, bloomSize)
+	if err != nil {
+		return err
+	}
+	t.filter = bloom(filter)
+	return nil
+}
+
+// readBlock 读取block并校验crc
+func (t *table) readBlock(offset int64, size int) ([]byte, error) {
+	buf := make([]byte, size+4)
+	if _, err := t.f.ReadAt(buf, offset); err != nil {
+		return nil, err
+	}
+	data := buf[:size]
+	if crc32.ChecksumIEEE(data) != binary.BigEndian.Uint32(buf[size:]) {
+		return nil, ErrCorrupt
+	}
+	return data, nil
+}
+
+// get 查找key，found 为false表示表中没有这个key
+func (t *table) get(key []byte) (e entry, found bool, err error) {
+	if !t.filter.mayContain(key) {
+		return
+	}
+	i := t.blockFor(key)
+	if i == len(t.index) {
+		return
+	}
+	it := &tableIter{t: t, block: i}
+	for it.next() {
+		switch c := bytes.Compare(it.cur.key, key); {
+		case c == 0:
+			return it.cur, true, nil
+		case c > 0:
+			return
+		}
+	}
+	return entry{}, false, it.err
+}
+
+// blockFor 返回第一个lastKey不小于key的数据块
+func (t *table) blockFor(key []byte) int {
+	return sort.Search(len(t.index), func(i int) bool {
+		return bytes.Compare(t.index[i].lastKey, key) >= 0
+	})
+}
+
+// seek 返回从第一个不小于start的记录开始的迭代器
+func (t *table) seek(start []byte) *tableIter {
+	it := &tableIter{t: t, start: start}
+	if start != nil {
+		it.block = t.blockFor(start)
+	}
+	return it
+}
+
+func (t *table) close() error {
+	return t.f.Close()
+}
+
+// tableIter 表文件的顺序迭代器
+type tableIter struct {
+	t     *table
+	block int    // 下一个要读取的数据块
+	data  []byte // 当前数据块中未读取的部分
+	start []byte // 跳过小于start的记录
+	cur   entry
+	err   error
+}
+
+// next 移动到下一条记录，没有更多记录或出错时返回false
+func (it *tableIter) next() bool {
+	for {
+		for len(it.data) == 0 {
+			if it.err != nil || it.block >= len(it.t.index) {
+				return false
+			}
+			ie := it.t.index[it.block]
+			it.data, it.err = it.t.readBlock(ie.offset, ie.size)
+			it.block++
+		}
+		var e entry
+		var err error
+		if e, it.data, err = decodeEntry(it.data); err != nil {
+			it.err, it.data = err, nil
+			return false
+		}
+		if it.start != nil && bytes.Compare(e.key, it.start) < 0 {
+			continue
+		}
+		it.start = nil
+		it.cur = e
+		return true
+	}
+}
+
+// decodeEntry 解析数据块中的一条记录
+func decodeEntry(data []byte) (e entry, rest []byte, err error) {
+	if e.key, rest, err = readBytes(data); err != nil {
+		return
+	}
+	if len(rest) == 0 {
+		err = ErrCorrupt
+		return
+	}
+	e.deleted = rest[0] == kindTombstone
+	e.value, rest, err = readBytes(rest[1:])
+	return
+}
+
+func readUvarint(data []byte) (uint64, []byte, error) {
+	v, n := binary.Uvarint(data)
+	if n <= 0 {
+		return 0, nil, ErrCorrupt
+	}
+	return v, data[n:], nil
+}
+
+func readBytes(data []byte) ([]byte, []byte, error) {
+	size, rest, err := readUvarint(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	if uint64(len(rest)) < size {
+		return nil, nil, ErrCorrupt
+	}
+	return rest[:size:size], rest[size:], nil
+}
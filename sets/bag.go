@@ -0,0 +1,208 @@
+package sets
+
+// Bag 泛型多重集合，记录每个元素的个数，使用 NewBag 创建
+type Bag[T comparable] map[T]int
+
+// NewBag 用元素创建多重集合，重复的元素会累加个数
+func NewBag[T comparable](items ...T) Bag[T] {
+	b := make(Bag[T], len(items))
+	b.Add(items...)
+	return b
+}
+
+// BagFromCounts 用元素到个数的map创建多重集合，个数<=0的元素被忽略
+func BagFromCounts[T comparable](counts map[T]int) Bag[T] {
+	b := make(Bag[T], len(counts))
+	for item, n := range counts {
+		b.AddN(item, n)
+	}
+	return b
+}
+
+// Add 每个元素个数加一
+func (b Bag[T]) Add(items ...T) {
+	for _, item := range items {
+		b[item]++
+	}
+}
+
+// AddN 元素个数加n，n<=0时不做处理
+func (b Bag[T]) AddN(item T, n int) {
+	if n > 0 {
+		b[item] += n
+	}
+}
+
+// Remove 每个元素个数减一，减到0时删除
+func (b Bag[T]) Remove(items ...T) {
+	for _, item := range items {
+		b.RemoveN(item, 1)
+	}
+}
+
+// RemoveN 元素个数减n，减到0时删除
+func (b Bag[T]) RemoveN(item T, n int) {
+	if n <= 0 {
+		return
+	}
+	if b[item] <= n {
+		delete(b, item)
+		return
+	}
+	b[item] -= n
+}
+
+// Count 元素的个数
+func (b Bag[T]) Count(item T) int {
+	return b[item]
+}
+
+// Len 元素总个数（包含重复）
+func (b Bag[T]) Len() int {
+	n := 0
+	for _, cnt := range b {
+		n += cnt
+	}
+	return n
+}
+
+// Distinct 不同元素的个数
+func (b Bag[T]) Distinct() int {
+	return len(b)
+}
+
+// Set 去重后的集合
+func (b Bag[T]) Set() Set[T] {
+	return FromMapKeys(b)
+}
+
+// Clone 复制多重集合
+func (b Bag[T]) Clone() Bag[T] {
+	c := make(Bag[T], len(b))
+	for item, n := range b {
+		c[item] = n
+	}
+	return c
+}
+
+// Slice 展开为切片，每个元素重复其个数次，顺序不固定，需要固定顺序时使用 SortFunc
+func (b Bag[T]) Slice(opts ...SliceOption[T]) []T {
+	ret := make([]T, 0, b.Len())
+	for item, n := range b {
+		for i := 0; i < n; i++ {
+			ret = append(ret, item)
+		}
+	}
+	return sortSlice(ret, opts)
+}
+
+// Intersect 交集，个数取两者较小值
+func (b Bag[T]) Intersect(o Bag[T]) Bag[T] {
+	ret := make(Bag[T])
+	for item, n := range b {
+		ret.AddN(item, min(n, o[item]))
+	}
+	return ret
+}
+
+// Union 并集，个数取两者较大值
+func (b Bag[T]) Union(o Bag[T]) Bag[T] {
+	ret := b.Clone()
+	for item, n := range o {
+		ret[item] = max(ret[item], n)
+	}
+	return ret
+}
+
+// Sum 和，个数相加
+func (b Bag[T]) Sum(o Bag[T]) Bag[T] {
+	ret := b.Clone()
+	for item, n := range o {
+		ret[item] += n
+	}
+	return ret
+}
+
+// Difference 差集，个数相减，减到0时删除
+func (b Bag[T]) Difference(o Bag[T]) Bag[T] {
+	ret := b.Clone()
+	for item, n := range o {
+		ret.RemoveN(item, n)
+	}
+	return ret
+}
+
+// IsSubset 每个元素的个数都不大于o中的个数
+func (b Bag[T]) IsSubset(o Bag[T]) bool {
+	for item, n := range b {
+		if n > o[item] {
+			return false
+		}
+	}
+	return true
+}
+
+// Equal 两个多重集合的元素及个数是否相同
+func (b Bag[T]) Equal(o Bag[T]) bool {
+	return len(b) == len(o) && b.IsSubset(o)
+}
+
+// BagIntersect 多重集合语义的交集，如 [a,a,b] ∩ [a,a,a] = [a,a]
+// 结果按a中的顺序排列
+func BagIntersect[T comparable](a, b []T, opts ...SliceOption[T]) (ret []T) {
+	remain := NewBag(b...)
+	for _, item := range a {
+		if remain[item] > 0 {
+			remain[item]--
+			ret = append(ret, item)
+		}
+	}
+	return sortSlice(ret, opts)
+}
+
+// BagUnion 多重集合语义的并集，个数取较大值，如 [a,a,b] ∪ [a,c] = [a,a,b,c]
+// 结果先按a后按b中的顺序排列
+func BagUnion[T comparable](a, b []T, opts ...SliceOption[T]) []T {
+	remain := NewBag(a...)
+	ret := append([]T(nil), a...)
+	for _, item := range b {
+		if remain[item] > 0 {
+			remain[item]--
+			continue
+		}
+		ret = append(ret, item)
+	}
+	return sortSlice(ret, opts)
+}
+
+// BagSum 多重集合语义的和，如 [a,b] + [a] = [a,b,a]
+func BagSum[T comparable](a, b []T, opts ...SliceOption[T]) []T {
+	ret := make([]T, 0, len(a)+len(b))
+	ret = append(append(ret, a...), b...)
+	return sortSlice(ret, opts)
+}
+
+// BagSub 多重集合语义的差集，如 [a,a,b] - [a] = [a,b]
+// 结果按a中的顺序排列，从前往后抵消
+func BagSub[T comparable](a, b []T, opts ...SliceOption[T]) (ret []T) {
+	remain := NewBag(b...)
+	for _, item := range a {
+		if remain[item] > 0 {
+			remain[item]--
+			continue
+		}
+		ret = append(ret, item)
+	}
+	return sortSlice(ret, opts)
+}
+
+// GetRepeatCounts 获得重复的元素及其个数
+func GetRepeatCounts[T comparable](set []T) map[T]int {
+	counts := NewBag(set...)
+	for item, n := range counts {
+		if n < 2 {
+			delete(counts, item)
+		}
+	}
+	return counts
+}
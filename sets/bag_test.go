@@ -0,0 +1,83 @@
+package sets
+
+import (
+	"cmp"
+	"slices"
+	"testing"
+)
+
+func TestBag(t *testing.T) {
+	a := NewBag("a", "a", "b")
+	b := BagFromCounts(map[string]int{"a": 1, "c": 2, "d": 0})
+	if a.Count("a") != 2 || a.Len() != 3 || a.Distinct() != 2 || b.Distinct() != 2 {
+		t.Fatalf("a = %v, b = %v", a, b)
+	}
+
+	tests := []struct {
+		name string
+		got  Bag[string]
+		want Bag[string]
+	}{
+		{"intersect", a.Intersect(b), NewBag("a")},
+		{"union", a.Union(b), NewBag("a", "a", "b", "c", "c")},
+		{"sum", a.Sum(b), NewBag("a", "a", "a", "b", "c", "c")},
+		{"difference", a.Difference(b), NewBag("a", "b")},
+		{"difference all", b.Difference(a), NewBag("c", "c")},
+	}
+	for _, tt := range tests {
+		if !tt.got.Equal(tt.want) {
+			t.Fatalf("%s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+	if a.Count("a") != 2 || b.Count("c") != 2 {
+		t.Fatal("operands modified")
+	}
+	if !NewBag("a").IsSubset(a) || NewBag("a", "a", "a").IsSubset(a) {
+		t.Fatal("subset")
+	}
+
+	c := a.Clone()
+	c.Remove("a", "b", "x")
+	c.RemoveN("a", 5)
+	if c.Len() != 0 || len(c) != 0 {
+		t.Fatalf("removed = %v", c)
+	}
+	if got := a.Slice(SortFunc(cmp.Compare[string])); !slices.Equal(got, []string{"a", "a", "b"}) {
+		t.Fatalf("slice = %v", got)
+	}
+	if !a.Set().Equal(New("a", "b")) {
+		t.Fatalf("set = %v", a.Set())
+	}
+}
+
+func TestBagSlices(t *testing.T) {
+	a := []string{"a", "b", "a", "c"}
+	b := []string{"c", "a", "d", "c"}
+	tests := []struct {
+		name string
+		got  []string
+		want []string
+	}{
+		{"intersect", BagIntersect(a, b), []string{"a", "c"}},
+		{"union", BagUnion(a, b), []string{"a", "b", "a", "c", "d", "c"}},
+		{"sum", BagSum(a, b), []string{"a", "b", "a", "c", "c", "a", "d", "c"}},
+		{"sub", BagSub(a, b), []string{"b", "a"}},
+		{"sub inventory", BagSub([]string{"a", "a", "b"}, []string{"a"}), []string{"a", "b"}},
+		{"sorted union", BagUnion(a, b, SortFunc(cmp.Compare[string])), []string{"a", "a", "b", "c", "c", "d"}},
+	}
+	for _, tt := range tests {
+		if !slices.Equal(tt.got, tt.want) {
+			t.Fatalf("%s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestGetRepeatCounts(t *testing.T) {
+	got := GetRepeatCounts([]string{"x", "y", "x", "z", "x", "y"})
+	if len(got) != 2 || got["x"] != 3 || got["y"] != 2 {
+		t.Fatalf("repeats = %v", got)
+	}
+	if got := GetRepeatCounts([]int{1, 2, 3}); len(got) != 0 {
+		t.Fatalf("no repeats = %v", got)
+	}
+}
@@ -0,0 +1,230 @@
+package sets
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// 通过反射按字段取key时的错误
+var (
+	ErrNotStruct        = errors.New("not struct")
+	ErrFieldNotFound    = errors.New("field not found")
+	ErrFieldUnexported  = errors.New("field unexported")
+	ErrNoKeyField       = errors.New("no field tagged sets:\"key\"")
+	ErrKeyNotComparable = errors.New("key not comparable")
+	ErrNilField         = errors.New("nil pointer on field path")
+)
+
+// keyTag 未指定字段时，用带有该tag的字段作为key
+const keyTag = "key"
+
+// FieldKey 通过反射从struct（或struct指针）中取key
+// 字段支持点分路径，如 "Profile.Uid"，中间的指针字段会自动解引用；
+// 多个字段组成复合key；未指定字段时使用带有 `sets:"key"` tag的字段
+type FieldKey[T any] struct {
+	typ    reflect.Type
+	paths  []string
+	index  [][]int
+	compos reflect.Type // 复合key的类型：[n]interface{}
+}
+
+// NewFieldKey 创建FieldKey，字段在创建时校验
+func NewFieldKey[T any](paths ...string) (*FieldKey[T], error) {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	st := indirectType(typ)
+	if st.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%s: %w", typ, ErrNotStruct)
+	}
+	fk := &FieldKey[T]{typ: typ}
+	if len(paths) == 0 {
+		if paths = taggedFields(st, "", nil); len(paths) == 0 {
+			return nil, fmt.Errorf("%s: %w", typ, ErrNoKeyField)
+		}
+	}
+	for _, path := range paths {
+		index, err := resolvePath(st, path)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", typ, err)
+		}
+		fk.paths = append(fk.paths, path)
+		fk.index = append(fk.index, index)
+	}
+	if len(fk.index) > 1 {
+		fk.compos = reflect.ArrayOf(len(fk.index), reflect.TypeOf((*any)(nil)).Elem())
+	}
+	return fk, nil
+}
+
+// Paths 组成key的字段
+func (fk *FieldKey[T]) Paths() []string {
+	return fk.paths
+}
+
+// Key 取元素的key，复合key为[n]interface{}，可以直接作为map的key
+func (fk *FieldKey[T]) Key(item T) (any, error) {
+	v := reflect.ValueOf(&item).Elem()
+	if fk.compos == nil {
+		return fk.field(v, 0)
+	}
+	key := reflect.New(fk.compos).Elem()
+	for i := range fk.index {
+		f, err := fk.field(v, i)
+		if err != nil {
+			return nil, err
+		}
+		if f != nil {
+			key.Index(i).Set(reflect.ValueOf(f))
+		}
+	}
+	return key.Interface(), nil
+}
+
+// field 按第i个字段的路径取值
+func (fk *FieldKey[T]) field(v reflect.Value, i int) (any, error) {
+	key, err := fieldValue(v, fk.index[i])
+	if err != nil {
+		return nil, fmt.Errorf("%s: %q: %w", fk.typ, fk.paths[i], err)
+	}
+	return key, nil
+}
+
+// fieldValue 按 resolvePath 解析出的下标取字段的值，路径上的指针自动解引用
+func fieldValue(v reflect.Value, index []int) (any, error) {
+	for _, idx := range index {
+		for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+			if v.IsNil() {
+				return nil, ErrNilField
+			}
+			v = v.Elem()
+		}
+		v = v.Field(idx)
+	}
+	if !v.Comparable() {
+		return nil, ErrKeyNotComparable
+	}
+	return v.Interface(), nil
+}
+
+// keys 计算所有元素的key
+func (fk *FieldKey[T]) keys(items []T) ([]any, error) {
+	keys := make([]any, len(items))
+	for i, item := range items {
+		k, err := fk.Key(item)
+		if err != nil {
+			return nil, fmt.Errorf("item %d: %w", i, err)
+		}
+		keys[i] = k
+	}
+	return keys, nil
+}
+
+// IntersectByField 同IntersectBy，key由paths中的字段决定，paths 为空时使用带有tag的字段，见FieldKey
+func IntersectByField[T any](a, b []T, paths []string, opts ...SliceOption[T]) ([]T, error) {
+	aKeys, bKeys, err := fieldKeys(a, b, paths)
+	if err != nil {
+		return nil, err
+	}
+	return sortSlice(intersectKeys(a, aKeys, bKeys), opts), nil
+}
+
+// UnionByField 同UnionBy，key由字段决定，见FieldKey
+func UnionByField[T any](a, b []T, paths []string, opts ...SliceOption[T]) ([]T, error) {
+	aKeys, bKeys, err := fieldKeys(a, b, paths)
+	if err != nil {
+		return nil, err
+	}
+	return sortSlice(unionKeys(a, b, aKeys, bKeys), opts), nil
+}
+
+// SubByField 同SubBy，key由字段决定，见FieldKey
+func SubByField[T any](a, b []T, paths []string, opts ...SliceOption[T]) ([]T, error) {
+	aKeys, bKeys, err := fieldKeys(a, b, paths)
+	if err != nil {
+		return nil, err
+	}
+	return sortSlice(subKeys(a, aKeys, bKeys), opts), nil
+}
+
+// DiffByField 同DiffBy，key由字段决定，见FieldKey
+func DiffByField[T any](a, b []T, paths []string, opts ...SliceOption[T]) (aSub, bSub, altA, altB []T, err error) {
+	aKeys, bKeys, err := fieldKeys(a, b, paths)
+	if err != nil {
+		return
+	}
+	aSub, bSub, altA, altB = diffKeys(a, b, aKeys, bKeys)
+	sortSlice(aSub, opts)
+	sortSlice(bSub, opts)
+	sortSlicePairs(altA, altB, opts)
+	return
+}
+
+func fieldKeys[T any](a, b []T, paths []string) (aKeys, bKeys []any, err error) {
+	fk, err := NewFieldKey[T](paths...)
+	if err != nil {
+		return
+	}
+	if aKeys, err = fk.keys(a); err != nil {
+		return
+	}
+	bKeys, err = fk.keys(b)
+	return
+}
+
+// resolvePath 解析点分路径为字段下标
+func resolvePath(t reflect.Type, path string) (index []int, err error) {
+	var walked []string
+	for _, name := range strings.Split(path, ".") {
+		t = indirectType(t)
+		if t.Kind() != reflect.Struct {
+			return nil, fmt.Errorf("%q: %s is %s: %w", path, strings.Join(walked, "."), t, ErrNotStruct)
+		}
+		walked = append(walked, name)
+		f, ok := t.FieldByName(name)
+		if !ok {
+			return nil, fmt.Errorf("%q: %s: %w", path, strings.Join(walked, "."), ErrFieldNotFound)
+		}
+		// 提升字段经过的嵌入字段也需要是导出的，否则无法取值
+		for i := range f.Index {
+			if !t.FieldByIndex(f.Index[:i+1]).IsExported() {
+				return nil, fmt.Errorf("%q: %s: %w", path, strings.Join(walked, "."), ErrFieldUnexported)
+			}
+		}
+		index = append(index, f.Index...)
+		t = f.Type
+	}
+	if !t.Comparable() {
+		return nil, fmt.Errorf("%q is %s: %w", path, t, ErrKeyNotComparable)
+	}
+	return
+}
+
+// taggedFields 递归查找带有 `sets:"key"` tag的导出字段，返回字段路径
+func taggedFields(t reflect.Type, prefix string, visiting []reflect.Type) (paths []string) {
+	for _, v := range visiting {
+		if v == t {
+			return
+		}
+	}
+	visiting = append(visiting, t)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		if f.Tag.Get("sets") == keyTag {
+			paths = append(paths, prefix+f.Name)
+		} else if ft := indirectType(f.Type); ft.Kind() == reflect.Struct {
+			paths = append(paths, taggedFields(ft, prefix+f.Name+".", visiting)...)
+		}
+	}
+	return
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
@@ -0,0 +1,110 @@
+package sets
+
+import (
+	"reflect"
+)
+
+// IntersectBy 根据keyFn求交集，返回a中key也在b中的元素
+// 相同key只保留第一个，结果按a中的顺序排列
+func IntersectBy[T any, K comparable](a, b []T, keyFn func(T) K, opts ...SliceOption[T]) []T {
+	return sortSlice(intersectKeys(a, keysOf(a, keyFn), keysOf(b, keyFn)), opts)
+}
+
+// UnionBy 根据keyFn求并集，相同key只保留第一个，先a后b
+func UnionBy[T any, K comparable](a, b []T, keyFn func(T) K, opts ...SliceOption[T]) []T {
+	return sortSlice(unionKeys(a, b, keysOf(a, keyFn), keysOf(b, keyFn)), opts)
+}
+
+// SubBy 根据keyFn求差集，返回a中key不在b中的元素
+func SubBy[T any, K comparable](a, b []T, keyFn func(T) K, opts ...SliceOption[T]) []T {
+	return sortSlice(subKeys(a, keysOf(a, keyFn), keysOf(b, keyFn)), opts)
+}
+
+// DiffBy 同DiffSlice，aSub：a相较于b的差集；bSub：b相较于a的差集；
+// altA, altB: key相同但值不相同（reflect.DeepEqual）的元素
+func DiffBy[T any, K comparable](a, b []T, keyFn func(T) K, opts ...SliceOption[T]) (aSub, bSub, altA, altB []T) {
+	aSub, bSub, altA, altB = diffKeys(a, b, keysOf(a, keyFn), keysOf(b, keyFn))
+	sortSlice(aSub, opts)
+	sortSlice(bSub, opts)
+	sortSlicePairs(altA, altB, opts)
+	return
+}
+
+// keysOf 计算每个元素的key
+func keysOf[T any, K comparable](items []T, keyFn func(T) K) []K {
+	keys := make([]K, len(items))
+	for i, item := range items {
+		keys[i] = keyFn(item)
+	}
+	return keys
+}
+
+// firstIndex key到第一次出现的下标
+func firstIndex[K comparable](keys []K) map[K]int {
+	m := make(map[K]int, len(keys))
+	for i, k := range keys {
+		if _, ok := m[k]; !ok {
+			m[k] = i
+		}
+	}
+	return m
+}
+
+func intersectKeys[T any, K comparable](a []T, aKeys, bKeys []K) (ret []T) {
+	in := New(bKeys...)
+	seen := make(Set[K])
+	for i, k := range aKeys {
+		if in.Has(k) && !seen.Has(k) {
+			seen.Add(k)
+			ret = append(ret, a[i])
+		}
+	}
+	return
+}
+
+func unionKeys[T any, K comparable](a, b []T, aKeys, bKeys []K) (ret []T) {
+	seen := make(Set[K], len(aKeys)+len(bKeys))
+	for i, k := range aKeys {
+		if !seen.Has(k) {
+			seen.Add(k)
+			ret = append(ret, a[i])
+		}
+	}
+	for i, k := range bKeys {
+		if !seen.Has(k) {
+			seen.Add(k)
+			ret = append(ret, b[i])
+		}
+	}
+	return
+}
+
+func subKeys[T any, K comparable](a []T, aKeys, bKeys []K) (ret []T) {
+	seen := New(bKeys...)
+	for i, k := range aKeys {
+		if !seen.Has(k) {
+			seen.Add(k)
+			ret = append(ret, a[i])
+		}
+	}
+	return
+}
+
+func diffKeys[T any, K comparable](a, b []T, aKeys, bKeys []K) (aSub, bSub, altA, altB []T) {
+	aSub = subKeys(a, aKeys, bKeys)
+	bSub = subKeys(b, bKeys, aKeys)
+	bIndex := firstIndex(bKeys)
+	seen := make(Set[K])
+	for i, k := range aKeys {
+		j, ok := bIndex[k]
+		if !ok || seen.Has(k) {
+			continue
+		}
+		seen.Add(k)
+		if !reflect.DeepEqual(a[i], b[j]) {
+			altA = append(altA, a[i])
+			altB = append(altB, b[j])
+		}
+	}
+	return
+}
@@ -0,0 +1,177 @@
+package sets
+
+import (
+	"cmp"
+	"errors"
+	"slices"
+	"testing"
+)
+
+type profile struct {
+	Uid  int64
+	Zone int32
+}
+
+type player struct {
+	Name    string
+	Profile *profile
+	Level   int
+	Tags    []string
+}
+
+type taggedPlayer struct {
+	Uid   int64 `sets:"key"`
+	Name  string
+	Shard struct {
+		Zone int32 `sets:"key"`
+	}
+}
+
+func names(ps []player) []string {
+	ret := make([]string, len(ps))
+	for i, p := range ps {
+		ret[i] = p.Name
+	}
+	return ret
+}
+
+func TestKeyFuncOps(t *testing.T) {
+	a := []player{{Name: "a1", Level: 1}, {Name: "b", Level: 2}, {Name: "a1", Level: 9}, {Name: "c", Level: 3}}
+	b := []player{{Name: "c", Level: 3}, {Name: "d"}, {Name: "b", Level: 5}}
+	byName := func(p player) string { return p.Name }
+
+	if got := names(IntersectBy(a, b, byName)); !slices.Equal(got, []string{"b", "c"}) {
+		t.Fatalf("intersect = %v", got)
+	}
+	if got := names(UnionBy(a, b, byName)); !slices.Equal(got, []string{"a1", "b", "c", "d"}) {
+		t.Fatalf("union = %v", got)
+	}
+	if got := names(SubBy(a, b, byName)); !slices.Equal(got, []string{"a1"}) {
+		t.Fatalf("sub = %v", got)
+	}
+	aSub, bSub, altA, altB := DiffBy(a, b, byName)
+	if !slices.Equal(names(aSub), []string{"a1"}) || !slices.Equal(names(bSub), []string{"d"}) {
+		t.Fatalf("diff = %v, %v", names(aSub), names(bSub))
+	}
+	if len(altA) != 1 || altA[0].Level != 2 || altB[0].Level != 5 {
+		t.Fatalf("alt = %v, %v", altA, altB)
+	}
+}
+
+func TestFieldKeyPaths(t *testing.T) {
+	a := []player{
+		{Name: "x", Profile: &profile{Uid: 1, Zone: 1}},
+		{Name: "y", Profile: &profile{Uid: 2, Zone: 1}},
+		{Name: "z", Profile: &profile{Uid: 2, Zone: 2}},
+	}
+	b := []player{{Name: "w", Profile: &profile{Uid: 2, Zone: 2}}}
+
+	got, err := IntersectByField(a, b, []string{"Profile.Uid"})
+	if err != nil || !slices.Equal(names(got), []string{"y"}) {
+		t.Fatalf("intersect by uid = %v, %v", names(got), err)
+	}
+	got, err = IntersectByField(a, b, []string{"Profile.Uid", "Profile.Zone"})
+	if err != nil || !slices.Equal(names(got), []string{"z"}) {
+		t.Fatalf("intersect by uid+zone = %v, %v", names(got), err)
+	}
+	got, err = SubByField(a, b, []string{"Profile.Zone"})
+	if err != nil || !slices.Equal(names(got), []string{"x"}) {
+		t.Fatalf("sub by zone = %v, %v", names(got), err)
+	}
+	byName := SortFunc(func(x, y player) int { return cmp.Compare(y.Name, x.Name) })
+	got, err = UnionByField(a, b, []string{"Profile.Uid"}, byName)
+	if err != nil || !slices.Equal(names(got), []string{"y", "x"}) {
+		t.Fatalf("sorted union by uid = %v, %v", names(got), err)
+	}
+	_, bSub, _, _, err := DiffByField(b, a, []string{"Profile.Zone", "Name"}, byName)
+	if err != nil || !slices.Equal(names(bSub), []string{"z", "y", "x"}) {
+		t.Fatalf("sorted diff = %v, %v", names(bSub), err)
+	}
+	ptrs := []*player{&a[0], &a[1]}
+	got2, err := UnionByField(ptrs, []*player{&b[0]}, []string{"Profile.Uid"})
+	if err != nil || len(got2) != 2 {
+		t.Fatalf("union of pointers = %v, %v", got2, err)
+	}
+}
+
+func TestFieldKeyTags(t *testing.T) {
+	fk, err := NewFieldKey[taggedPlayer]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(fk.Paths(), []string{"Uid", "Shard.Zone"}) {
+		t.Fatalf("paths = %v", fk.Paths())
+	}
+	a := []taggedPlayer{{Uid: 1, Name: "a"}, {Uid: 2, Name: "b"}}
+	b := []taggedPlayer{{Uid: 1, Name: "c"}}
+	b[0].Shard.Zone = 7
+	a[1].Shard.Zone = 7
+	aSub, bSub, altA, _, err := DiffByField(a, b, nil)
+	if err != nil || len(aSub) != 2 || len(bSub) != 1 || altA != nil {
+		t.Fatalf("diff = %v, %v, %v, %v", aSub, bSub, altA, err)
+	}
+}
+
+func TestFieldKeyErrors(t *testing.T) {
+	type inner struct{ X int }
+	type hidden struct {
+		inner
+		secret int
+		Slice  []int
+	}
+	tests := []struct {
+		name  string
+		paths []string
+		want  error
+	}{
+		{"missing", []string{"Profile.Nope"}, ErrFieldNotFound},
+		{"not struct", []string{"Name.Len"}, ErrNotStruct},
+		{"not comparable", []string{"Tags"}, ErrKeyNotComparable},
+		{"no tag", nil, ErrNoKeyField},
+	}
+	for _, tt := range tests {
+		if _, err := IntersectByField([]player{{}}, nil, tt.paths); !errors.Is(err, tt.want) {
+			t.Fatalf("%s: err = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+	for _, path := range []string{"secret", "X"} {
+		if _, err := NewFieldKey[hidden](path); !errors.Is(err, ErrFieldUnexported) {
+			t.Fatalf("%s: err = %v", path, err)
+		}
+	}
+	if _, err := NewFieldKey[int]("X"); !errors.Is(err, ErrNotStruct) {
+		t.Fatalf("int: err = %v", err)
+	}
+	if _, err := SubByField([]player{{Name: "a"}}, nil, []string{"Profile.Uid"}); !errors.Is(err, ErrNilField) {
+		t.Fatalf("nil profile: err = %v", err)
+	}
+}
+
+func TestLegacyKeyNoPanic(t *testing.T) {
+	type hidden struct {
+		secret int
+		Name   string
+	}
+	a := []player{{Name: "x", Profile: &profile{Uid: 1}}, {Name: "nil profile"}, {Name: "y", Profile: &profile{Uid: 2}}}
+	b := []*player{{Name: "z", Profile: &profile{Uid: 2}}, nil}
+
+	// 路径上有nil指针的元素被跳过
+	if got := Intersect(a, b, "Profile.Uid"); len(got) != 1 || got[0].(player).Name != "y" {
+		t.Fatalf("intersect = %v", got)
+	}
+	if got := Sub(a, b, "Profile.Uid"); len(got) != 1 || got[0].(player).Name != "x" {
+		t.Fatalf("sub = %v", got)
+	}
+	// 字段不存在或不可导出时结果为空
+	for _, key := range []string{"Nope", "Tags"} {
+		if got := Union(a, b, key); len(got) != 0 {
+			t.Fatalf("union by %s = %v", key, got)
+		}
+	}
+	if got := Intersect([]hidden{{1, "a"}}, []hidden{{1, "a"}}, "secret"); len(got) != 0 {
+		t.Fatalf("intersect by unexported = %v", got)
+	}
+	if got := Intersect([]interface{}{1, player{Name: "n"}}, []player{{Name: "n"}}, "Name"); len(got) != 1 {
+		t.Fatalf("mixed types = %v", got)
+	}
+}
@@ -0,0 +1,96 @@
+package sets
+
+import (
+	"slices"
+)
+
+// options 返回 []interface{} 的集合运算的可选参数
+type options struct {
+	cmp func(a, b any) int
+}
+
+// Option 返回 []interface{} 的集合运算（Intersect、Union、Sub、DiffSlice）的可选参数
+// 泛型的集合运算使用 SliceOption
+type Option func(*options)
+
+// SortBy 结果按cmp排序（稳定排序），默认结果按输入切片的顺序排列
+// T 需要与结果中元素的动态类型一致，如对 []interface{} 结果中的int64排序使用 SortBy[int64]，
+// 类型不一致时排序会panic
+func SortBy[T any](cmp func(a, b T) int) Option {
+	return func(o *options) {
+		o.cmp = func(a, b any) int { return cmp(a.(T), b.(T)) }
+	}
+}
+
+// sliceOptions 泛型集合运算的可选参数
+type sliceOptions[T any] struct {
+	cmp func(a, b T) int
+}
+
+// SliceOption 泛型集合运算的可选参数，元素类型在编译时检查
+type SliceOption[T any] func(*sliceOptions[T])
+
+// SortFunc 结果按cmp排序（稳定排序），默认结果按输入切片的顺序排列
+func SortFunc[T any](cmp func(a, b T) int) SliceOption[T] {
+	return func(o *sliceOptions[T]) {
+		o.cmp = cmp
+	}
+}
+
+// sortResult 按 SortBy 对 []interface{} 结果排序
+func sortResult(items []interface{}, opts []Option) []interface{} {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.cmp != nil {
+		slices.SortStableFunc(items, o.cmp)
+	}
+	return items
+}
+
+// sortResultPairs 按a对成对的 []interface{} 结果排序，b跟随a
+func sortResultPairs(a, b []interface{}, opts []Option) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.cmp != nil {
+		sortPairsFunc(a, b, o.cmp)
+	}
+}
+
+// sortSlice 按 SortFunc 对结果排序
+func sortSlice[T any](items []T, opts []SliceOption[T]) []T {
+	if cmp := sliceCmp(opts); cmp != nil {
+		slices.SortStableFunc(items, cmp)
+	}
+	return items
+}
+
+// sortSlicePairs 按a对成对的结果排序，b跟随a
+func sortSlicePairs[T any](a, b []T, opts []SliceOption[T]) {
+	if cmp := sliceCmp(opts); cmp != nil {
+		sortPairsFunc(a, b, cmp)
+	}
+}
+
+func sliceCmp[T any](opts []SliceOption[T]) func(a, b T) int {
+	var o sliceOptions[T]
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o.cmp
+}
+
+func sortPairsFunc[T any](a, b []T, cmp func(a, b T) int) {
+	idx := make([]int, len(a))
+	for i := range idx {
+		idx[i] = i
+	}
+	slices.SortStableFunc(idx, func(i, j int) int { return cmp(a[i], a[j]) })
+	sa, sb := slices.Clone(a), slices.Clone(b)
+	for i, j := range idx {
+		a[i], b[i] = sa[j], sb[j]
+	}
+}
@@ -0,0 +1,85 @@
+package sets
+
+import (
+	"cmp"
+	"slices"
+	"testing"
+)
+
+func TestOrderPreserved(t *testing.T) {
+	a := []int64{9, 4, 7, 1, 4, 3}
+	b := []int64{3, 8, 1, 9, 2}
+	want := func(name string, got []interface{}, want ...int64) {
+		t.Helper()
+		conv, err := ConvInt64s(got)
+		if err != nil || !slices.Equal(conv, want) {
+			t.Fatalf("%s = %v, want %v", name, got, want)
+		}
+	}
+	// map遍历顺序随机，多跑几次
+	for i := 0; i < 20; i++ {
+		want("intersect", Intersect(a, b, ""), 9, 1, 3)
+		want("union", Union(a, b, ""), 9, 4, 7, 1, 3, 8, 2)
+		want("sub", Sub(a, b, ""), 4, 7)
+		aSub, bSub, _, _ := DiffSlice(a, b, "")
+		want("diff a", aSub, 4, 7)
+		want("diff b", bSub, 8, 2)
+		if got := GetRepeatItem([]int32{5, 2, 2, 9, 5, 2, 1}); !EqualInt32s(got, []int32{5, 2}) {
+			t.Fatalf("repeat = %v", got)
+		}
+	}
+
+	asc := SortBy(cmp.Compare[int64])
+	want("sorted intersect", Intersect(a, b, "", asc), 1, 3, 9)
+	want("sorted union", Union(a, b, "", asc), 1, 2, 3, 4, 7, 8, 9)
+	if got := GetRepeatItem([]int32{5, 2, 2, 5}, SortFunc(cmp.Compare[int32])); !EqualInt32s(got, []int32{2, 5}) {
+		t.Fatalf("sorted repeat = %v", got)
+	}
+	if got := New(3, 1, 2).Slice(SortFunc(cmp.Compare[int])); !slices.Equal(got, []int{1, 2, 3}) {
+		t.Fatalf("sorted set = %v", got)
+	}
+}
+
+func TestOrderPreservedWithKey(t *testing.T) {
+	type item struct {
+		ID  int
+		Val string
+	}
+	a := []item{{3, "a"}, {1, "b"}, {2, "c"}}
+	b := []*item{{2, "x"}, {3, "a"}, {1, "y"}}
+	for i := 0; i < 20; i++ {
+		_, _, altA, altB := DiffSlice(a, b, "ID")
+		if len(altA) != 2 || altA[0].(item).ID != 1 || altB[0].(item).Val != "y" {
+			t.Fatalf("alt = %v, %v", altA, altB)
+		}
+	}
+	desc := SortBy(func(x, y item) int { return cmp.Compare(y.ID, x.ID) })
+	_, _, altA, altB := DiffSlice(a, b, "ID", desc)
+	if altA[0].(item).ID != 2 || altB[0].(item).Val != "x" || altB[1].(item).Val != "y" {
+		t.Fatalf("sorted alt = %v, %v", altA, altB)
+	}
+	byID := func(x item) int { return x.ID }
+	got := UnionBy(a, []item{{0, "z"}}, byID, SortFunc(func(x, y item) int { return cmp.Compare(x.ID, y.ID) }))
+	if got[0].ID != 0 || got[3].ID != 3 {
+		t.Fatalf("sorted union = %v", got)
+	}
+}
+
+func TestDuplicateKeysKeepFirst(t *testing.T) {
+	type item struct {
+		ID  int
+		Val string
+	}
+	a := []item{{1, "first"}, {2, "x"}, {1, "last"}}
+	b := []item{{1, "b-first"}, {1, "b-last"}, {3, "y"}}
+	if got := Intersect(a, b, "ID"); len(got) != 1 || got[0].(item).Val != "first" {
+		t.Fatalf("intersect = %v", got)
+	}
+	if got := Union(a, b, "ID"); len(got) != 3 || got[0].(item).Val != "first" || got[2].(item).ID != 3 {
+		t.Fatalf("union = %v", got)
+	}
+	_, _, altA, altB := DiffSlice(a, b, "ID")
+	if len(altA) != 1 || altA[0].(item).Val != "first" || altB[0].(item).Val != "b-first" {
+		t.Fatalf("alt = %v, %v", altA, altB)
+	}
+}
@@ -0,0 +1,197 @@
+package sets
+
+// Set 泛型集合，零值不能直接添加元素，使用 New 创建
+type Set[T comparable] map[T]struct{}
+
+// New 用元素创建集合
+func New[T comparable](items ...T) Set[T] {
+	s := make(Set[T], len(items))
+	s.Add(items...)
+	return s
+}
+
+// FromSlice 用切片中的元素创建集合
+func FromSlice[T comparable](items []T) Set[T] {
+	return New(items...)
+}
+
+// FromMapKeys 用map的key创建集合
+func FromMapKeys[K comparable, V any](m map[K]V) Set[K] {
+	s := make(Set[K], len(m))
+	for k := range m {
+		s[k] = struct{}{}
+	}
+	return s
+}
+
+// Add 添加元素
+func (s Set[T]) Add(items ...T) {
+	for _, item := range items {
+		s[item] = struct{}{}
+	}
+}
+
+// Remove 删除元素
+func (s Set[T]) Remove(items ...T) {
+	for _, item := range items {
+		delete(s, item)
+	}
+}
+
+// Has 元素是否在集合中
+func (s Set[T]) Has(item T) bool {
+	_, ok := s[item]
+	return ok
+}
+
+// Len 元素个数
+func (s Set[T]) Len() int {
+	return len(s)
+}
+
+// Clone 复制集合
+func (s Set[T]) Clone() Set[T] {
+	c := make(Set[T], len(s))
+	for item := range s {
+		c[item] = struct{}{}
+	}
+	return c
+}
+
+// Slice 转换为切片，顺序不固定，需要固定顺序时使用 SortFunc
+func (s Set[T]) Slice(opts ...SliceOption[T]) []T {
+	ret := make([]T, 0, len(s))
+	for item := range s {
+		ret = append(ret, item)
+	}
+	return sortSlice(ret, opts)
+}
+
+// Union 求并集
+func (s Set[T]) Union(o Set[T]) Set[T] {
+	ret := s.Clone()
+	for item := range o {
+		ret[item] = struct{}{}
+	}
+	return ret
+}
+
+// Intersect 求交集
+func (s Set[T]) Intersect(o Set[T]) Set[T] {
+	small, big := s, o
+	if len(small) > len(big) {
+		small, big = big, small
+	}
+	ret := make(Set[T])
+	for item := range small {
+		if big.Has(item) {
+			ret[item] = struct{}{}
+		}
+	}
+	return ret
+}
+
+// Difference 求差集，即在s中不在o中的元素
+func (s Set[T]) Difference(o Set[T]) Set[T] {
+	ret := make(Set[T])
+	for item := range s {
+		if !o.Has(item) {
+			ret[item] = struct{}{}
+		}
+	}
+	return ret
+}
+
+// SymmetricDifference 求对称差集，即只在其中一个集合中的元素
+func (s Set[T]) SymmetricDifference(o Set[T]) Set[T] {
+	ret := s.Difference(o)
+	for item := range o {
+		if !s.Has(item) {
+			ret[item] = struct{}{}
+		}
+	}
+	return ret
+}
+
+// IsSubset s 是否是o的子集
+func (s Set[T]) IsSubset(o Set[T]) bool {
+	if len(s) > len(o) {
+		return false
+	}
+	for item := range s {
+		if !o.Has(item) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSuperset s 是否是o的超集
+func (s Set[T]) IsSuperset(o Set[T]) bool {
+	return o.IsSubset(s)
+}
+
+// IsDisjoint 两个集合是否没有共同的元素
+func (s Set[T]) IsDisjoint(o Set[T]) bool {
+	small, big := s, o
+	if len(small) > len(big) {
+		small, big = big, small
+	}
+	for item := range small {
+		if big.Has(item) {
+			return false
+		}
+	}
+	return true
+}
+
+// Equal 两个集合的元素是否相同
+func (s Set[T]) Equal(o Set[T]) bool {
+	return len(s) == len(o) && s.IsSubset(o)
+}
+
+// IntersectSlices 求两个切片的交集，结果去重并按a中首次出现的顺序排列
+func IntersectSlices[T comparable](a, b []T, opts ...SliceOption[T]) []T {
+	in := New(b...)
+	return sortSlice(filterUnique(a, func(item T) bool { return in.Has(item) }), opts)
+}
+
+// UnionSlices 求两个切片的并集，结果去重，先按a后按b中首次出现的顺序排列
+func UnionSlices[T comparable](a, b []T, opts ...SliceOption[T]) []T {
+	seen := make(Set[T], len(a)+len(b))
+	var ret []T
+	for _, items := range [2][]T{a, b} {
+		for _, item := range items {
+			if !seen.Has(item) {
+				seen.Add(item)
+				ret = append(ret, item)
+			}
+		}
+	}
+	return sortSlice(ret, opts)
+}
+
+// SubSlices 求a相较于b的差集，结果去重并按a中首次出现的顺序排列
+func SubSlices[T comparable](a, b []T, opts ...SliceOption[T]) []T {
+	in := New(b...)
+	return sortSlice(filterUnique(a, func(item T) bool { return !in.Has(item) }), opts)
+}
+
+// DiffSlices aSub：a相较于b的差集；bSub：b相较于a的差集
+func DiffSlices[T comparable](a, b []T, opts ...SliceOption[T]) (aSub, bSub []T) {
+	return SubSlices(a, b, opts...), SubSlices(b, a, opts...)
+}
+
+// filterUnique 按顺序返回满足keep的元素，重复的元素只保留第一个
+func filterUnique[T comparable](items []T, keep func(T) bool) []T {
+	seen := make(Set[T], len(items))
+	var ret []T
+	for _, item := range items {
+		if seen.Has(item) || !keep(item) {
+			continue
+		}
+		seen.Add(item)
+		ret = append(ret, item)
+	}
+	return ret
+}
@@ -0,0 +1,80 @@
+package sets
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSetAlgebra(t *testing.T) {
+	a := New(1, 2, 3, 4)
+	b := FromSlice([]int{3, 4, 5})
+	if a.Len() != 4 || !a.Has(1) || a.Has(5) {
+		t.Fatalf("a = %v", a)
+	}
+
+	tests := []struct {
+		name string
+		got  Set[int]
+		want Set[int]
+	}{
+		{"union", a.Union(b), New(1, 2, 3, 4, 5)},
+		{"intersect", a.Intersect(b), New(3, 4)},
+		{"difference", a.Difference(b), New(1, 2)},
+		{"symmetric", a.SymmetricDifference(b), New(1, 2, 5)},
+	}
+	for _, tt := range tests {
+		if !tt.got.Equal(tt.want) {
+			t.Fatalf("%s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+	if a.Len() != 4 || b.Len() != 3 {
+		t.Fatal("operands modified")
+	}
+
+	sub := New(3, 4)
+	if !sub.IsSubset(a) || !a.IsSuperset(sub) || a.IsSubset(sub) {
+		t.Fatal("subset")
+	}
+	if a.IsDisjoint(b) || !New(1).IsDisjoint(New(2)) {
+		t.Fatal("disjoint")
+	}
+	if a.Equal(b) || !New[int]().Equal(Set[int]{}) {
+		t.Fatal("equal")
+	}
+
+	c := a.Clone()
+	c.Remove(1, 2)
+	c.Add(9)
+	if !c.Equal(New(3, 4, 9)) || !a.Has(1) {
+		t.Fatalf("clone = %v, a = %v", c, a)
+	}
+	got := c.Slice()
+	slices.Sort(got)
+	if !slices.Equal(got, []int{3, 4, 9}) {
+		t.Fatalf("slice = %v", got)
+	}
+	if keys := FromMapKeys(map[string]int{"x": 1, "y": 2}); !keys.Equal(New("x", "y")) {
+		t.Fatalf("map keys = %v", keys)
+	}
+}
+
+func TestSliceHelpers(t *testing.T) {
+	a := []int64{5, 1, 3, 1, 7}
+	b := []int64{7, 3, 8, 3}
+	if got := IntersectSlices(a, b); !slices.Equal(got, []int64{3, 7}) {
+		t.Fatalf("intersect = %v", got)
+	}
+	if got := UnionSlices(a, b); !slices.Equal(got, []int64{5, 1, 3, 7, 8}) {
+		t.Fatalf("union = %v", got)
+	}
+	if got := SubSlices(a, b); !slices.Equal(got, []int64{5, 1}) {
+		t.Fatalf("sub = %v", got)
+	}
+	aSub, bSub := DiffSlices(a, b)
+	if !slices.Equal(aSub, []int64{5, 1}) || !slices.Equal(bSub, []int64{8}) {
+		t.Fatalf("diff = %v, %v", aSub, bSub)
+	}
+	if got := IntersectSlices([]string{"a"}, nil); got != nil {
+		t.Fatalf("intersect with empty = %v", got)
+	}
+}
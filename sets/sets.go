@@ -27,8 +27,8 @@ func Contain(tar, arr interface{}) (exist bool, err error) {
 	return
 }
 
-//GetRepeatItem 获得重复的元素
-func GetRepeatItem(set []int32) (repeats []int32) {
+//GetRepeatItem 获得重复的元素，按第一次出现的顺序排列
+func GetRepeatItem(set []int32, opts ...SliceOption[int32]) (repeats []int32) {
 	if len(set) == 0 {
 		return
 	}
@@ -36,117 +36,104 @@ func GetRepeatItem(set []int32) (repeats []int32) {
 	for _, item := range set {
 		m[item]++
 	}
-	for item, cnt := range m {
-		if cnt > 1 {
+	for _, item := range set {
+		if m[item] > 1 {
 			repeats = append(repeats, item)
+			m[item] = 0
 		}
 	}
+	repeats = sortSlice(repeats, opts)
 	return
 }
 
 // Intersect 求交集
 // struct 类型slice 支持根据字段名求交集
-func Intersect(aSet, bSet interface{}, key string) (iArr []interface{}) {
-	aMap := make(map[interface{}]interface{}, 0)
-	bMap := make(map[interface{}]interface{}, 0)
-	if key == "" {
-		aMap = arr2map(aSet)
-		bMap = arr2map(bSet)
-	} else {
-		aMap = arr2mapWithKey(aSet, key)
-		bMap = arr2mapWithKey(bSet, key)
-	}
-	iArr = IntersectMap(aMap, bMap)
+// 结果按aSet中的顺序排列，可以通过 SortBy 指定排序
+// key相同的元素只保留第一个（早期版本保留最后一个），下同
+// 无法按key取值的元素（字段不存在、不可导出、路径上有nil指针）会被跳过，不会panic，下同；
+// 需要得到具体错误时使用 IntersectByField 等 *ByField 函数
+func Intersect(aSet, bSet interface{}, key string, opts ...Option) (iArr []interface{}) {
+	aKeys, aValues := arr2list(aSet, key)
+	bKeys, _ := arr2list(bSet, key)
+	iArr = sortResult(intersectKeys(aValues, aKeys, bKeys), opts)
 	return
 }
 
 // Union 求并集
-func Union(aSet, bSet interface{}, key string) (uArr []interface{}) {
-	aMap := make(map[interface{}]interface{}, 0)
-	bMap := make(map[interface{}]interface{}, 0)
-	if key == "" {
-		aMap = arr2map(aSet)
-		bMap = arr2map(bSet)
-	} else {
-		aMap = arr2mapWithKey(aSet, key)
-		bMap = arr2mapWithKey(bSet, key)
-	}
-	uArr = UnionMap(aMap, bMap)
+// 结果先按aSet后按bSet中的顺序排列，key相同的元素只保留第一个
+func Union(aSet, bSet interface{}, key string, opts ...Option) (uArr []interface{}) {
+	aKeys, aValues := arr2list(aSet, key)
+	bKeys, bValues := arr2list(bSet, key)
+	uArr = sortResult(unionKeys(aValues, bValues, aKeys, bKeys), opts)
 	return
 }
 
 // Sub 求差集
-func Sub(aSet, bSet interface{}, key string) (aSub []interface{}) {
-	aMap := make(map[interface{}]interface{}, 0)
-	bMap := make(map[interface{}]interface{}, 0)
-	if key == "" {
-		aMap = arr2map(aSet)
-		bMap = arr2map(bSet)
-	} else {
-		aMap = arr2mapWithKey(aSet, key)
-		bMap = arr2mapWithKey(bSet, key)
-	}
-	aSub = SubMap(aMap, bMap)
+// 结果按aSet中的顺序排列，key相同的元素只保留第一个
+func Sub(aSet, bSet interface{}, key string, opts ...Option) (aSub []interface{}) {
+	aKeys, aValues := arr2list(aSet, key)
+	bKeys, _ := arr2list(bSet, key)
+	aSub = sortResult(subKeys(aValues, aKeys, bKeys), opts)
 	return
 }
 
 // DiffSlice aSub：a相较于b的差集；bSub：b相较于a的差集；
 // altA, altB: 未指定key时没有意义，指定key时，字段相同值不相同的结果
 // a,b: struct slice 可以通过key指定struct中的字段，进行计算结果
-func DiffSlice(a, b interface{}, key string) (aSub, bSub, altA, altB []interface{}) {
-	aMap := make(map[interface{}]interface{}, 0)
-	bMap := make(map[interface{}]interface{}, 0)
-	if key == "" {
-		aMap = arr2map(a)
-		bMap = arr2map(b)
-	} else {
-		aMap = arr2mapWithKey(a, key)
-		bMap = arr2mapWithKey(b, key)
-	}
-	aSub = SubMap(aMap, bMap)
-	bSub = SubMap(bMap, aMap)
-	altA, altB = CompareMap(aMap, bMap)
-	return
-}
-
-// arr2map 切片转map
-func arr2map(arr interface{}) (aMap map[interface{}]interface{}) {
-	aMap = make(map[interface{}]interface{}, 0)
-	s := reflect.ValueOf(arr)
-	if s.Kind() != reflect.Slice {
-		// todo log
-		return
-	}
-	for i := 0; i < s.Len(); i++ {
-		value := s.Index(i).Interface()
-		aMap[value] = value
-	}
+// 结果按各自输入的顺序排列，altB与altA一一对应
+// key重复时用第一个元素计算差集和比较值
+func DiffSlice(a, b interface{}, key string, opts ...Option) (aSub, bSub, altA, altB []interface{}) {
+	aKeys, aValues := arr2list(a, key)
+	bKeys, bValues := arr2list(b, key)
+	aSub, bSub, altA, altB = diffKeys(aValues, bValues, aKeys, bKeys)
+	sortResult(aSub, opts)
+	sortResult(bSub, opts)
+	sortResultPairs(altA, altB, opts)
 	return
 }
 
-// arr2mapWithKey
-// struct切片转map 带key（即指定字段名，注意是字段名）
-func arr2mapWithKey(arr interface{}, key string) (aMap map[interface{}]interface{}) {
-	aMap = make(map[interface{}]interface{}, 0)
+// arr2list 切片转key和value列表，保持切片中的顺序
+// key为空时key就是元素本身，否则为struct中对应字段的值（注意是字段名，支持 "Profile.Uid" 形式的路径）
+// 不是struct、字段不存在或不可导出、路径上有nil指针的元素会被跳过；需要得到错误时使用 *ByField 系列函数
+func arr2list(arr interface{}, key string) (keys, values []interface{}) {
 	aSlice := reflect.ValueOf(arr)
 	if aSlice.Kind() != reflect.Slice {
 		// todo log
 		return
 	}
+	// 元素类型可能不同（如 []interface{}），按类型缓存字段下标
+	indexes := make(map[reflect.Type][]int)
 	for i := 0; i < aSlice.Len(); i++ {
 		value := aSlice.Index(i)
-		var iv interface{}
-		if value.Kind() == reflect.Ptr {
-			iv = value.Elem().Interface()
-		} else {
-			iv = value.Interface()
+		if key == "" {
+			keys = append(keys, value.Interface())
+			values = append(values, value.Interface())
+			continue
 		}
-		if reflect.ValueOf(iv).Kind() != reflect.Struct {
+		for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+			if value.IsNil() {
+				break
+			}
+			value = value.Elem()
+		}
+		if value.Kind() != reflect.Struct {
 			// todo log
-			return
+			continue
+		}
+		index, ok := indexes[value.Type()]
+		if !ok {
+			index, _ = resolvePath(value.Type(), key)
+			indexes[value.Type()] = index
+		}
+		if index == nil {
+			continue
+		}
+		ikey, err := fieldValue(value, index)
+		if err != nil {
+			continue
 		}
-		ikey := reflect.ValueOf(iv).FieldByName(key).Interface()
-		aMap[ikey] = iv
+		keys = append(keys, ikey)
+		values = append(values, value.Interface())
 	}
 	return
 }